@@ -1,54 +1,55 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	Addressing "gemelos/pkg/addressing"
-
-	RandomData "github.com/Pallinder/go-randomdata"
+	Gemini "gemelos/pkg/gemini"
+	Tools "gemelos/pkg/tools"
+	"math/rand"
+	"time"
 )
 
 const NetworkNodesCount = 6000
 const AddressCaseLength = 3
-const HatClubSize = 6000/2 ^ 3
-const BootClubSize = 6000/2 ^ 3
+const HatClubSize = NetworkNodesCount >> AddressCaseLength
+const BootClubSize = NetworkNodesCount >> AddressCaseLength
 
 type (
 	Stats struct {
-		HatClubs       map[string][]*Addressing.Address
-		BootClubs      map[string][]*Addressing.Address
+		HatClubs       map[Addressing.Case][]*Addressing.Address
+		BootClubs      map[Addressing.Case][]*Addressing.Address
 		HatClubsCount  int
 		BootClubsCount int
 	}
 )
 
-func GetRandomIp() string {
-	return RandomData.IpV4Address()
+func GetRandomIp(random *rand.Rand) string {
+	return fmt.Sprintf("%d.%d.%d.%d", random.Intn(256), random.Intn(256), random.Intn(256), random.Intn(256))
 }
 
-func GetAddress() *Addressing.Address {
-	return Addressing.NewAddress(GetRandomIp(), true)
+func GetAddress(random *rand.Rand) *Addressing.Address {
+	return Addressing.NewAddress(GetRandomIp(random), true)
 }
 
 func Categorize(stats *Stats, addr *Addressing.Address) {
-	addrBinHash := addr.GetBinaryHash()
-
-	hatcase := addrBinHash[0:AddressCaseLength]
-	bootcase := addrBinHash[len(addrBinHash)-1-AddressCaseLength : len(addrBinHash)-1]
+	hatcase := addr.Prefix(AddressCaseLength)
+	bootcase := addr.Suffix(AddressCaseLength)
 
-	if _, exists := stats.HatClubs[string(hatcase)]; exists {
-		stats.HatClubs[string(hatcase)] = append(stats.HatClubs[string(hatcase)], addr)
+	if _, exists := stats.HatClubs[hatcase]; exists {
+		stats.HatClubs[hatcase] = append(stats.HatClubs[hatcase], addr)
 	} else {
-		stats.HatClubs[string(hatcase)] = append(
+		stats.HatClubs[hatcase] = append(
 			make([]*Addressing.Address, 0, HatClubSize),
 			addr,
 		)
 		stats.HatClubsCount++
 	}
 
-	if _, exists := stats.BootClubs[string(bootcase)]; exists {
-		stats.BootClubs[string(bootcase)] = append(stats.BootClubs[string(bootcase)], addr)
+	if _, exists := stats.BootClubs[bootcase]; exists {
+		stats.BootClubs[bootcase] = append(stats.BootClubs[bootcase], addr)
 	} else {
-		stats.BootClubs[string(bootcase)] = append(
+		stats.BootClubs[bootcase] = append(
 			make([]*Addressing.Address, 0, BootClubSize),
 			addr,
 		)
@@ -57,9 +58,13 @@ func Categorize(stats *Stats, addr *Addressing.Address) {
 }
 
 func PrintStats(stats *Stats) {
+	gParams := Gemini.NewGeminiConfig(NetworkNodesCount, 160, AddressCaseLength, AddressCaseLength)
+
 	fmt.Println("Stats:")
 	fmt.Println("*) HatClubs:")
 	fmt.Println("*---> Count:", stats.HatClubsCount)
+	fmt.Println("*---> Expected Count:", gParams.ExpectedClubCount(Gemini.Hat))
+	fmt.Println("*---> Expected Size:", gParams.ExpectedClubSize(Gemini.Hat))
 	fmt.Println("*---> Clubs/Length/Values:")
 	fmt.Println(" ")
 	for k, v := range stats.HatClubs {
@@ -73,6 +78,8 @@ func PrintStats(stats *Stats) {
 
 	fmt.Printf("\n\n*) BootClubs:\n")
 	fmt.Println("*---> Count:", stats.BootClubsCount)
+	fmt.Println("*---> Expected Count:", gParams.ExpectedClubCount(Gemini.Boot))
+	fmt.Println("*---> Expected Size:", gParams.ExpectedClubSize(Gemini.Boot))
 	fmt.Println("*---> Clubs/Length:")
 	fmt.Println(" ")
 	for k, v := range stats.BootClubs {
@@ -87,8 +94,8 @@ func PrintStats(stats *Stats) {
 
 func GetStatsObj() *Stats {
 	return &Stats{
-		HatClubs:       make(map[string][]*Addressing.Address),
-		BootClubs:      make(map[string][]*Addressing.Address),
+		HatClubs:       make(map[Addressing.Case][]*Addressing.Address),
+		BootClubs:      make(map[Addressing.Case][]*Addressing.Address),
 		HatClubsCount:  0,
 		BootClubsCount: 0,
 	}
@@ -103,14 +110,25 @@ func isAddressUnique(as []*Addressing.Address, addr *Addressing.Address) bool {
 	return true
 }
 
+// main draws the addresses from -seed, the time if unset, and prints the
+// seed so a run can be replayed.
 func main() {
+	seed := flag.Int64("seed", 0, "seed of the random addresses, the time if 0")
+	flag.Parse()
+
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	fmt.Println("Seed:", *seed)
+	random := rand.New(Tools.NewSource(*seed))
+
 	stats := GetStatsObj()
 
 	addressCount := 0
 	addressPool := make([]*Addressing.Address, 0, 6000)
 
 	for addressCount < NetworkNodesCount {
-		addr := GetAddress()
+		addr := GetAddress(random)
 		if isAddressUnique(addressPool, addr) {
 			addressPool = append(addressPool, addr)
 			addressCount++
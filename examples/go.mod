@@ -3,7 +3,6 @@ module gemelos
 go 1.16
 
 require (
-	github.com/Pallinder/go-randomdata v1.2.0 // indirect
-	github.com/google/uuid v1.3.0 // indirect
 	github.com/hbollon/go-edlib v1.3.4
+	golang.org/x/crypto v0.0.0-20210921155107-089bfa567519
 )
@@ -1,11 +1,8 @@
 package addressing
 
 import (
-	"crypto/sha1"
 	"fmt"
 	"math/big"
-
-	gUUID "github.com/google/uuid"
 )
 
 const Delimiter string = "@"
@@ -23,11 +20,18 @@ type (
 		GetHash() []byte
 		GetBinaryHash() []byte
 		GetRaw() string
+		GetSalt() []byte
+		// Deprecated: ids are no longer salted with the host UUID, use
+		// GetSalt.
 		GetUUID() []byte
 		GetStatus() Status
 		String() string
 		GetBitLength() int
 		GetBinBitLength() int
+		Bit(int) uint
+		Prefix(int) Case
+		Suffix(int) Case
+		Slice(int, int) Case
 	}
 
 	Address struct {
@@ -35,15 +39,31 @@ type (
 		Raw    string
 		Hashed []byte
 		Status Status
+		Hasher Hasher
 	}
 )
 
 func NewAddress(addr string, hash ...bool) *Address {
+	return NewAddressWithHasher(addr, DefaultHasher, hash...)
+}
+
+func NewAddressWithHasher(addr string, hasher Hasher, hash ...bool) *Address {
+	return NewDerivedAddress(addr, hasher, DefaultSalter, hash...)
+}
+
+// NewDerivedAddress salts the id with public data only, so any peer that
+// knows the raw address, the hasher and the salter derives the same id.
+func NewDerivedAddress(addr string, hasher Hasher, salter Salter, hash ...bool) *Address {
+	if salter == nil {
+		salter = DefaultSalter
+	}
+
 	a := &Address{
-		ID:     gUUID.NodeID(),
+		ID:     salter.Salt(idInput(addr)),
 		Raw:    addr,
 		Hashed: nil,
 		Status: Raw,
+		Hasher: hasher,
 	}
 
 	if len(hash) > 0 && hash[0] == true {
@@ -58,11 +78,14 @@ func (a *Address) Hash() {
 		return
 	}
 
-	// TODO: decouple this using an interface
-	h := sha1.New()
+	if a.Hasher == nil {
+		a.Hasher = DefaultHasher
+	}
+
+	h := a.Hasher.New()
 	h.Write(a.ID)
 	h.Write([]byte(Delimiter))
-	h.Write([]byte(a.Raw))
+	h.Write([]byte(idInput(a.Raw)))
 	a.Hashed = h.Sum(nil)
 	a.Status = Hashed
 }
@@ -83,18 +106,25 @@ func (a *Address) GetRaw() string {
 	return a.Raw
 }
 
-func (a *Address) GetUUID() []byte {
+func (a *Address) GetSalt() []byte {
 	return a.ID
 }
 
+// Deprecated: GetUUID returns the salt, use GetSalt.
+func (a *Address) GetUUID() []byte {
+	return a.GetSalt()
+}
+
 func (a *Address) String() string {
 	return fmt.Sprintf("Raw: %s, Hash: %b", a.Raw, a.Hashed)
 }
 
+// GetBinaryHash spells out every bit of the hash, leading zeros included,
+// so its length is always GetBitLength.
 func (a *Address) GetBinaryHash() []byte {
 	var binRep big.Int
 	(&binRep).SetBytes(a.Hashed)
-	return []byte(fmt.Sprintf("%b", &binRep))
+	return []byte(fmt.Sprintf("%0*b", len(a.Hashed)*8, &binRep))
 }
 
 func (a *Address) GetBitLength() int {
@@ -110,3 +140,25 @@ func (a *Address) GetBinBitLength() int {
 	}
 	return -1
 }
+
+// Bit returns the i-th bit of the hash, counting from the most
+// significant one.
+func (a *Address) Bit(i int) uint {
+	return bit(a.Hashed, i)
+}
+
+// Prefix returns the first n bits of the hash.
+func (a *Address) Prefix(n int) Case {
+	return caseAt(a.Hashed, 0, n)
+}
+
+// Suffix returns the last n bits of the hash.
+func (a *Address) Suffix(n int) Case {
+	return caseAt(a.Hashed, len(a.Hashed)*8-n, n)
+}
+
+// Slice returns length bits of the hash starting at offset, counted from
+// the most significant bit.
+func (a *Address) Slice(offset, length int) Case {
+	return caseAt(a.Hashed, offset, length)
+}
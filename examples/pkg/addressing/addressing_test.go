@@ -1,6 +1,9 @@
 package addressing
 
-import "testing"
+import (
+	"fmt"
+	"testing"
+)
 
 func TestNewAddress(t *testing.T) {
 	addr := NewAddress("10.10.123.123")
@@ -46,3 +49,129 @@ func TestNewAddressWithImmediateHashing(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestNewAddressWithHasher(t *testing.T) {
+	hashers := []Hasher{SHA1Hasher{}, SHA256Hasher{}, Blake2bHasher{}, KeccakHasher{}}
+
+	for _, h := range hashers {
+		addr := NewAddressWithHasher("10.10.123.123", h, true)
+
+		if len(addr.GetHash()) != h.Size() {
+			t.Log("Faulty Address Hash Length for", h.Name())
+			t.Fail()
+		}
+
+		if addr.GetBitLength() != h.Size()*8 {
+			t.Log("Faulty Address Bit Length for", h.Name())
+			t.Fail()
+		}
+	}
+
+	sha256Addr := NewAddressWithHasher("10.10.123.123", SHA256Hasher{}, true)
+	keccakAddr := NewAddressWithHasher("10.10.123.123", KeccakHasher{}, true)
+
+	if string(sha256Addr.GetHash()) == string(keccakAddr.GetHash()) {
+		t.Log("Different hashers produced the same hash")
+		t.Fail()
+	}
+}
+
+func TestDeterministicAddress(t *testing.T) {
+	a := NewAddress("10.10.123.123", true)
+	b := NewAddress(" 10.10.123.123 ", true)
+
+	if string(a.GetHash()) != string(b.GetHash()) {
+		t.Log("Same address hashed to different ids")
+		t.Fail()
+	}
+
+	if !VerifyAddress("10.10.123.123", a.GetHash(), DefaultHasher, DefaultSalter) {
+		t.Log("Peer id could not be recomputed from its address")
+		t.Fail()
+	}
+
+	salted := NewDerivedAddress("10.10.123.123", DefaultHasher, StaticSalt("pocket-testnet"), true)
+	if string(salted.GetHash()) == string(a.GetHash()) {
+		t.Log("Salt did not change the derived id")
+		t.Fail()
+	}
+
+	if string(salted.GetUUID()) != string(salted.GetSalt()) || string(salted.GetSalt()) != "pocket-testnet" {
+		t.Log("GetUUID should keep returning the salt")
+		t.Fail()
+	}
+
+	if VerifyAddress("10.10.123.124", a.GetHash(), DefaultHasher, DefaultSalter) {
+		t.Log("Id verified against the wrong address")
+		t.Fail()
+	}
+}
+
+func TestCanonicalAddress(t *testing.T) {
+	canonical := map[string]string{
+		"10.10.123.123":        "10.10.123.123",
+		" Node.Pokt.Network ":  "node.pokt.network",
+		"[::ffff:10.0.0.1]:80": "10.0.0.1:80",
+		"2001:DB8:0:0:0:0:0:1": "2001:db8::1",
+		"[2001:db8::1]:8080":   "[2001:db8::1]:8080",
+		"10.10.230.331":        "10.10.230.331",
+	}
+
+	for raw, expected := range canonical {
+		if CanonicalAddress(raw) != expected {
+			t.Log("Faulty canonical address for", raw, CanonicalAddress(raw))
+			t.Fail()
+		}
+	}
+}
+
+func TestBinaryHashWidth(t *testing.T) {
+	for i := 0; i < 256; i++ {
+		addr := NewAddress(fmt.Sprintf("10.10.10.%d", i), true)
+
+		if len(addr.GetBinaryHash()) != addr.GetBitLength() {
+			t.Log("Binary hash dropped leading zeros", addr.GetRaw())
+			t.Fail()
+		}
+	}
+}
+
+func TestBitLevelCases(t *testing.T) {
+	addr := &Address{Hashed: []byte{0x0f, 0x00, 0x81}, Status: Hashed}
+
+	if addr.Bit(0) != 0 || addr.Bit(4) != 1 || addr.Bit(23) != 1 {
+		t.Log("Faulty bit extraction")
+		t.Fail()
+	}
+
+	if addr.Prefix(6) != (Case{Bits: 3, Length: 6}) {
+		t.Log("Faulty prefix", addr.Prefix(6))
+		t.Fail()
+	}
+
+	if addr.Suffix(3) != (Case{Bits: 1, Length: 3}) || addr.Suffix(3).String() != "001" {
+		t.Log("Faulty suffix", addr.Suffix(3))
+		t.Fail()
+	}
+
+	if addr.Prefix(3) == addr.Suffix(3) {
+		t.Log("Different cases compare equal")
+		t.Fail()
+	}
+
+	other := &Address{Hashed: []byte{0x0e, 0xff, 0x01}, Status: Hashed}
+	if addr.Prefix(6) != other.Prefix(6) || addr.Suffix(7) != other.Suffix(7) {
+		t.Log("Equal cases compare different")
+		t.Fail()
+	}
+
+	if addr.Slice(4, 8) != (Case{Bits: 0xf0, Length: 8}) || addr.Slice(0, 6) != addr.Prefix(6) {
+		t.Log("Faulty slice", addr.Slice(4, 8))
+		t.Fail()
+	}
+
+	if (Case{Bits: 1, Length: 3}) == (Case{Bits: 1, Length: 4}) {
+		t.Log("Cases of different lengths compare equal")
+		t.Fail()
+	}
+}
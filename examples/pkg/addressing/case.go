@@ -0,0 +1,43 @@
+package addressing
+
+import "fmt"
+
+// MaxCaseLength is the widest case a Case value can hold.
+const MaxCaseLength = 64
+
+// Case is a run of bits cut out of a hashed id, right aligned in Bits.
+// Cases are plain values, so they compare with == and can key maps.
+type Case struct {
+	Bits   uint64
+	Length int
+}
+
+func (c Case) Equal(o Case) bool {
+	return c == o
+}
+
+func (c Case) String() string {
+	if c.Length == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%0*b", c.Length, c.Bits)
+}
+
+// bit reads the i-th bit of a hash, counting from the most significant one.
+func bit(hashed []byte, i int) uint {
+	return uint(hashed[i/8]>>(7-uint(i%8))) & 1
+}
+
+// caseAt cuts length bits out of a hash starting at offset, counted from
+// the most significant bit.
+func caseAt(hashed []byte, offset, length int) Case {
+	if length < 0 || length > MaxCaseLength || offset < 0 || offset+length > len(hashed)*8 {
+		panic(fmt.Sprintf("addressing: case [%d:%d] out of range for a %d bit id", offset, offset+length, len(hashed)*8))
+	}
+
+	c := Case{Length: length}
+	for i := offset; i < offset+length; i++ {
+		c.Bits = c.Bits<<1 | uint64(bit(hashed, i))
+	}
+	return c
+}
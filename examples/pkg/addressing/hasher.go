@@ -0,0 +1,48 @@
+package addressing
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"hash"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
+)
+
+type (
+	Hasher interface {
+		New() hash.Hash
+		Size() int
+		Name() string
+	}
+
+	SHA1Hasher    struct{}
+	SHA256Hasher  struct{}
+	Blake2bHasher struct{}
+	KeccakHasher  struct{}
+)
+
+// DefaultHasher keeps the 160-bit SHA-1 ids the network started with.
+var DefaultHasher Hasher = SHA1Hasher{}
+
+func (SHA1Hasher) New() hash.Hash { return sha1.New() }
+func (SHA1Hasher) Size() int      { return sha1.Size }
+func (SHA1Hasher) Name() string   { return "sha1" }
+
+func (SHA256Hasher) New() hash.Hash { return sha256.New() }
+func (SHA256Hasher) Size() int      { return sha256.Size }
+func (SHA256Hasher) Name() string   { return "sha256" }
+
+func (Blake2bHasher) New() hash.Hash {
+	// New256 only fails on keys longer than 64 bytes
+	h, _ := blake2b.New256(nil)
+	return h
+}
+func (Blake2bHasher) Size() int    { return blake2b.Size256 }
+func (Blake2bHasher) Name() string { return "blake2b-256" }
+
+// KeccakHasher is the legacy Keccak-256 used by Ethereum, not the
+// standardized SHA3-256 padding.
+func (KeccakHasher) New() hash.Hash { return sha3.NewLegacyKeccak256() }
+func (KeccakHasher) Size() int      { return 32 }
+func (KeccakHasher) Name() string   { return "keccak256" }
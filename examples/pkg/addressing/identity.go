@@ -0,0 +1,94 @@
+package addressing
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+)
+
+// NodeIdentity binds a node to an ed25519 keypair. Its raw address has the
+// form <hex public key>@<endpoint> and the ring id is derived from the key
+// alone, so a node keeps its ring position when its endpoint changes and
+// cannot claim another position without the matching private key.
+type NodeIdentity struct {
+	PrivateKey ed25519.PrivateKey
+	PublicKey  ed25519.PublicKey
+}
+
+// NewNodeIdentity generates a fresh keypair, reading from crypto/rand when
+// no source is given.
+func NewNodeIdentity(source io.Reader) (*NodeIdentity, error) {
+	if source == nil {
+		source = rand.Reader
+	}
+
+	pub, priv, err := ed25519.GenerateKey(source)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NodeIdentity{PrivateKey: priv, PublicKey: pub}, nil
+}
+
+// NewNodeIdentityFromKey wraps an existing key, such as the one a Pocket
+// node already runs with.
+func NewNodeIdentityFromKey(priv ed25519.PrivateKey) (*NodeIdentity, error) {
+	if len(priv) != ed25519.PrivateKeySize {
+		return nil, errors.New("Wrong ed25519 private key length")
+	}
+
+	return &NodeIdentity{
+		PrivateKey: priv,
+		PublicKey:  priv.Public().(ed25519.PublicKey),
+	}, nil
+}
+
+func (id *NodeIdentity) Raw(endpoint string) string {
+	return hex.EncodeToString(id.PublicKey) + Delimiter + endpoint
+}
+
+func (id *NodeIdentity) Address(endpoint string, hasher Hasher, salter Salter) *Address {
+	return NewDerivedAddress(id.Raw(endpoint), hasher, salter, true)
+}
+
+func (id *NodeIdentity) Sign(msg []byte) []byte {
+	return ed25519.Sign(id.PrivateKey, msg)
+}
+
+func (id *NodeIdentity) Verify(msg, sig []byte) bool {
+	return VerifySignature(id.PublicKey, msg, sig)
+}
+
+func VerifySignature(pub ed25519.PublicKey, msg, sig []byte) bool {
+	if len(pub) != ed25519.PublicKeySize {
+		return false
+	}
+	return ed25519.Verify(pub, msg, sig)
+}
+
+// PublicKeyOf extracts the public key out of an identity raw address.
+func PublicKeyOf(addr string) (ed25519.PublicKey, bool) {
+	i := strings.Index(addr, Delimiter)
+	if i != hex.EncodedLen(ed25519.PublicKeySize) {
+		return nil, false
+	}
+
+	pub, err := hex.DecodeString(addr[:i])
+	if err != nil {
+		return nil, false
+	}
+
+	return ed25519.PublicKey(pub), true
+}
+
+// Endpoint is the part of a raw address a transport can reach, which is
+// the whole address unless it is an identity raw address.
+func Endpoint(addr string) string {
+	if _, ok := PublicKeyOf(addr); ok {
+		return addr[strings.Index(addr, Delimiter)+1:]
+	}
+	return addr
+}
@@ -0,0 +1,84 @@
+package addressing
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"testing"
+)
+
+func TestNewNodeIdentity(t *testing.T) {
+	id, err := NewNodeIdentity(nil)
+	if err != nil {
+		t.Log("Faulty identity generation", err)
+		t.Fail()
+	}
+
+	msg := []byte("block 42")
+	sig := id.Sign(msg)
+
+	if !id.Verify(msg, sig) {
+		t.Log("Identity does not verify its own signature")
+		t.Fail()
+	}
+
+	if VerifySignature(id.PublicKey, []byte("block 43"), sig) {
+		t.Log("Signature verified for a different message")
+		t.Fail()
+	}
+
+	other, _ := NewNodeIdentity(nil)
+	if other.Verify(msg, sig) {
+		t.Log("Signature verified under a different key")
+		t.Fail()
+	}
+}
+
+func TestNewNodeIdentityFromKey(t *testing.T) {
+	seed := bytes.Repeat([]byte{7}, ed25519.SeedSize)
+	priv := ed25519.NewKeyFromSeed(seed)
+
+	id, err := NewNodeIdentityFromKey(priv)
+	if err != nil || !bytes.Equal(id.PublicKey, priv.Public().(ed25519.PublicKey)) {
+		t.Log("Faulty identity from an existing key", err)
+		t.Fail()
+	}
+
+	if _, err := NewNodeIdentityFromKey(priv[:10]); err == nil {
+		t.Log("Truncated key should be rejected")
+		t.Fail()
+	}
+}
+
+func TestIdentityAddress(t *testing.T) {
+	id, _ := NewNodeIdentity(nil)
+
+	a := id.Address("10.0.0.1:4000", DefaultHasher, DefaultSalter)
+	b := id.Address("10.0.0.2:4000", DefaultHasher, DefaultSalter)
+
+	if !bytes.Equal(a.GetHash(), b.GetHash()) {
+		t.Log("Identity id should not depend on its endpoint")
+		t.Fail()
+	}
+
+	if Endpoint(a.GetRaw()) != "10.0.0.1:4000" || Endpoint("10.0.0.1:4000") != "10.0.0.1:4000" {
+		t.Log("Faulty endpoint extraction")
+		t.Fail()
+	}
+
+	pub, ok := PublicKeyOf(a.GetRaw())
+	if !ok || !bytes.Equal(pub, id.PublicKey) {
+		t.Log("Faulty public key extraction")
+		t.Fail()
+	}
+
+	if !VerifyAddress(b.GetRaw(), a.GetHash(), DefaultHasher, DefaultSalter) {
+		t.Log("Identity id could not be recomputed from its raw address")
+		t.Fail()
+	}
+
+	other, _ := NewNodeIdentity(nil)
+	if bytes.Equal(other.Address("10.0.0.1:4000", DefaultHasher, DefaultSalter).GetHash(), a.GetHash()) {
+		t.Log("Different keys derived the same id")
+		t.Fail()
+	}
+}
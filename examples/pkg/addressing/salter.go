@@ -0,0 +1,68 @@
+package addressing
+
+import (
+	"bytes"
+	"net"
+	"strings"
+)
+
+type (
+	// Salter picks the salt an id is derived from. Every input has to be
+	// public, otherwise peers cannot recompute each other's ids.
+	Salter interface {
+		Salt(canonical string) []byte
+	}
+
+	// NoSalt derives ids from the canonical address alone.
+	NoSalt struct{}
+
+	// StaticSalt shares one salt across the whole network, e.g. a chain id,
+	// so separate networks do not end up with the same id space.
+	StaticSalt []byte
+)
+
+var DefaultSalter Salter = NoSalt{}
+
+func (NoSalt) Salt(string) []byte { return nil }
+
+func (s StaticSalt) Salt(string) []byte { return []byte(s) }
+
+// CanonicalAddress normalizes the spellings of a network address that
+// point to the same peer, so that they all hash to the same id.
+func CanonicalAddress(addr string) string {
+	addr = strings.ToLower(strings.TrimSpace(addr))
+
+	if _, ok := PublicKeyOf(addr); ok {
+		i := strings.Index(addr, Delimiter)
+		return addr[:i] + Delimiter + CanonicalAddress(addr[i+1:])
+	}
+
+	if host, port, err := net.SplitHostPort(addr); err == nil {
+		if ip := net.ParseIP(host); ip != nil {
+			host = ip.String()
+		}
+		return net.JoinHostPort(host, port)
+	}
+
+	if ip := net.ParseIP(strings.Trim(addr, "[]")); ip != nil {
+		return ip.String()
+	}
+
+	return addr
+}
+
+// idInput is what an id gets derived from: the public key of identity raw
+// addresses, the canonical address otherwise.
+func idInput(addr string) string {
+	canonical := CanonicalAddress(addr)
+	if _, ok := PublicKeyOf(canonical); ok {
+		return canonical[:strings.Index(canonical, Delimiter)]
+	}
+	return canonical
+}
+
+// VerifyAddress recomputes the id of a raw address and checks it against
+// the one a peer claims.
+func VerifyAddress(addr string, id []byte, hasher Hasher, salter Salter) bool {
+	return bytes.Equal(NewDerivedAddress(addr, hasher, salter, true).GetHash(), id)
+}
@@ -0,0 +1,97 @@
+package gemini
+
+import (
+	Addressing "gemelos/pkg/addressing"
+	Peer "gemelos/pkg/peer"
+)
+
+// Broadcast hands payload to every node of the network, exactly once each.
+// The node sends it to the members of all its clubs, and every node getting
+// it for the first time relays it into its clubs but the one it came
+// through: from a Hat club into a Boot club, which spans the other Hat
+// clubs, and back. Copies are told apart by the sender and nonce of the
+// original message. The origin does not get its own payload back.
+//
+// BroadcastRedundancy bounds how many members of each club a node relays
+// to. Serve has to be running on every node for the broadcast to spread.
+func (g *Geminus) Broadcast(payload []byte) error {
+	if g.Transport == nil {
+		return ErrNoTransport
+	}
+
+	m := Peer.NewMessage(Peer.Broadcast, g.Addr.GetRaw())
+	m.Payload = payload
+	g.sign(m)
+
+	g.deliveries.visit(m)
+	g.relay(m, "", "")
+
+	return nil
+}
+
+// answerBroadcast unwraps a relayed broadcast, drops the copies already
+// seen and relays the others further.
+func (g *Geminus) answerBroadcast(m *Peer.Message) {
+	via := Club(m.Club)
+	if !g.Params.HasClub(via) {
+		return
+	}
+
+	original, err := Peer.Decode(m.Payload)
+	if err != nil || original.Type != Peer.Broadcast || original.Club != "" {
+		return
+	}
+	if _, signed := Addressing.PublicKeyOf(original.Sender); signed && !original.Verify() {
+		return
+	}
+
+	if !g.deliveries.visit(original) {
+		return
+	}
+
+	if g.OnBroadcast != nil {
+		g.OnBroadcast(original.Sender, original.Payload)
+	}
+
+	g.relay(original, via, m.Sender)
+}
+
+// relay wraps original for every club but via and sends it to up to
+// BroadcastRedundancy healthy members of each, leaving out the origin and
+// the node it came from.
+func (g *Geminus) relay(original *Peer.Message, via Club, from string) {
+	payload := Peer.Encode(original)
+	skip := []Addressing.Addr{g.newAddress(original.Sender)}
+	if from != "" {
+		skip = append(skip, g.newAddress(from))
+	}
+
+	state := g.healthy(g.clubState())
+	for _, club := range g.Params.clubNames() {
+		if club == via {
+			continue
+		}
+
+		members := make([]Addressing.Addr, 0, len(state.Clubs[club]))
+		for _, v := range state.Clubs[club] {
+			if indexOf(skip, v) < 0 {
+				members = append(members, v)
+			}
+		}
+
+		if redundancy := g.Params.BroadcastRedundancy; redundancy > 0 && redundancy < len(members) {
+			g.Params.random().Shuffle(len(members), func(i, j int) { members[i], members[j] = members[j], members[i] })
+			members = members[:redundancy]
+		}
+
+		m := Peer.NewMessage(Peer.Broadcast, g.Addr.GetRaw())
+		m.Club = string(club)
+		m.Payload = payload
+		g.sign(m)
+		wrapped := Peer.Encode(m)
+
+		for _, v := range members {
+			g.Transport.Send(Addressing.Endpoint(v.GetRaw()), wrapped)
+		}
+	}
+}
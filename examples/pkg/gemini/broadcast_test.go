@@ -0,0 +1,89 @@
+package gemini
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// broadcastCoverage broadcasts from the first node and returns how many
+// times every other node got the payload once the broadcast settled.
+func broadcastCoverage(t *testing.T, redundancy int) map[string]int {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	nodes := newMemoryNodes(ctx, 300, func() *GeminiConfig {
+		gParams := NewGeminiConfig(300, 160, 3, 3)
+		gParams.BroadcastRedundancy = redundancy
+		return gParams
+	})
+
+	var mu sync.Mutex
+	received := make(map[string]int)
+	for _, g := range nodes {
+		g := g
+		g.OnBroadcast = func(origin string, payload []byte) {
+			if origin != nodes[0].Addr.GetRaw() || string(payload) != "block" {
+				t.Error("Broadcast altered on the way", origin, string(payload))
+			}
+			mu.Lock()
+			received[g.Addr.GetRaw()]++
+			mu.Unlock()
+		}
+	}
+
+	if err := nodes[0].Broadcast([]byte("block")); err != nil {
+		t.Fatal("Faulty broadcast", err)
+	}
+
+	// settled once nobody new got it for a while
+	for last := -1; ; {
+		time.Sleep(100 * time.Millisecond)
+		mu.Lock()
+		count := len(received)
+		mu.Unlock()
+		if count == last {
+			break
+		}
+		last = count
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	return received
+}
+
+func TestBroadcast(t *testing.T) {
+	received := broadcastCoverage(t, 0)
+
+	if len(received) != 299 {
+		t.Log("Every node should get a broadcast relayed to every club member", len(received))
+		t.Fail()
+	}
+
+	for addr, count := range received {
+		if count != 1 {
+			t.Log("Every node should get a broadcast exactly once", addr, count)
+			t.Fail()
+		}
+	}
+}
+
+func TestBroadcastRedundancy(t *testing.T) {
+	received := broadcastCoverage(t, 4)
+	t.Log("Coverage with a redundancy of 4:", len(received), "of 299")
+
+	if len(received) < 270 {
+		t.Log("A redundancy of 4 should still reach nearly every node", len(received))
+		t.Fail()
+	}
+
+	for addr, count := range received {
+		if count != 1 {
+			t.Log("Every node should get a broadcast exactly once", addr, count)
+			t.Fail()
+		}
+	}
+}
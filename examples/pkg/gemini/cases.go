@@ -0,0 +1,131 @@
+package gemini
+
+import (
+	"errors"
+	"fmt"
+	Addressing "gemelos/pkg/addressing"
+)
+
+const (
+	Head         Club = "Head"
+	Body         Club = "Body"
+	Tail         Club = "Tail"
+	ReversedHead Club = "ReversedHead"
+	ReversedTail Club = "ReversedTail"
+)
+
+// CaseDefinition tells which bits of an id make up a case, and so which
+// club a node keeps for it. Offset counts from the most significant bit;
+// negative offsets count back from the end of the id, so a suffix of n
+// bits sits at -n whatever the address length.
+//
+// A Reversed case reads the node's own bits where the definition says but
+// the peer's bits at the mirrored position, so a reversed head collects the
+// peers whose tail equals our head.
+type CaseDefinition struct {
+	Name     Club
+	Offset   int
+	Length   int
+	Reversed bool
+}
+
+// DefaultCases is the original Hat (id prefix) and Boot (id suffix) layout.
+func DefaultCases(hatLength, bootLength int) []CaseDefinition {
+	return []CaseDefinition{
+		{Name: Hat, Offset: 0, Length: hatLength},
+		{Name: Boot, Offset: -bootLength, Length: bootLength},
+	}
+}
+
+// HeadBodyTailCases adds a third case taken around the middle of the id.
+func HeadBodyTailCases(addrLength, headLength, bodyLength, tailLength int) []CaseDefinition {
+	return []CaseDefinition{
+		{Name: Head, Offset: 0, Length: headLength},
+		{Name: Body, Offset: addrLength/2 - 1 - bodyLength/2, Length: bodyLength},
+		{Name: Tail, Offset: -tailLength, Length: tailLength},
+	}
+}
+
+// HeadTailReversedCases pairs head and tail with their mirrored clubs, so a
+// node also knows the peers whose head is its tail and the other way round.
+func HeadTailReversedCases(headLength, tailLength int) []CaseDefinition {
+	return []CaseDefinition{
+		{Name: Head, Offset: 0, Length: headLength},
+		{Name: Tail, Offset: -tailLength, Length: tailLength},
+		{Name: ReversedHead, Offset: 0, Length: headLength, Reversed: true},
+		{Name: ReversedTail, Offset: -tailLength, Length: tailLength, Reversed: true},
+	}
+}
+
+// start resolves the offset of the case for an id of addrLength bits.
+func (cd CaseDefinition) start(addrLength int) int {
+	if cd.Offset < 0 {
+		return addrLength + cd.Offset
+	}
+	return cd.Offset
+}
+
+// Of returns the node's own case.
+func (cd CaseDefinition) Of(haddr Addressing.Addr) Addressing.Case {
+	return haddr.Slice(cd.start(haddr.GetBitLength()), cd.Length)
+}
+
+// PeerCase returns the bits of a peer compared against the node's case,
+// which are the mirrored ones for reversed cases.
+func (cd CaseDefinition) PeerCase(haddr Addressing.Addr) Addressing.Case {
+	if !cd.Reversed {
+		return cd.Of(haddr)
+	}
+	addrLength := haddr.GetBitLength()
+	return haddr.Slice(addrLength-cd.start(addrLength)-cd.Length, cd.Length)
+}
+
+func (cd CaseDefinition) validate(addrLength int) error {
+	if cd.Name == "" || cd.Name == Unrecognized {
+		return errors.New("Case definitions need a club name")
+	}
+	if cd.Length <= 0 || cd.Length > Addressing.MaxCaseLength {
+		return fmt.Errorf("Case %s length should be between 1 and %d bits", cd.Name, Addressing.MaxCaseLength)
+	}
+	if start := cd.start(addrLength); start < 0 || start+cd.Length > addrLength {
+		return fmt.Errorf("Case %s does not fit a %d bit address", cd.Name, addrLength)
+	}
+	return nil
+}
+
+// ValidateCases checks the case definitions fit the address length and
+// name distinct clubs.
+func (gc *GeminiConfig) ValidateCases() error {
+	if len(gc.Cases) == 0 {
+		return errors.New("Gemini needs at least one case definition")
+	}
+
+	names := make(map[Club]bool, len(gc.Cases))
+	for _, cd := range gc.Cases {
+		if err := cd.validate(gc.AddrLength); err != nil {
+			return err
+		}
+		if names[cd.Name] {
+			return errors.New("Duplicate case definition " + string(cd.Name))
+		}
+		names[cd.Name] = true
+	}
+
+	return nil
+}
+
+// Case looks up the definition of a club.
+func (gc *GeminiConfig) Case(club Club) (CaseDefinition, bool) {
+	for _, cd := range gc.Cases {
+		if cd.Name == club {
+			return cd, true
+		}
+	}
+	return CaseDefinition{}, false
+}
+
+// primary is the case routing converges on; its clubs are contiguous
+// arcs of the ring as long as it is a prefix.
+func (gc *GeminiConfig) primary() CaseDefinition {
+	return gc.Cases[0]
+}
@@ -0,0 +1,125 @@
+package gemini
+
+import (
+	"fmt"
+	Addressing "gemelos/pkg/addressing"
+	"testing"
+)
+
+func TestNewGeminusWithCases(t *testing.T) {
+	gParams := NewGeminiConfigWithCases(6000, 160, HeadBodyTailCases(160, 3, 4, 3))
+	g := NewGeminus("10.10.210.21", gParams)
+
+	if err := g.Init(); err != nil {
+		t.Log("Faulty case definitions", err)
+		t.Fail()
+	}
+
+	for _, club := range []Club{Head, Body, Tail} {
+		if cap(g.Clubs[club]) != gParams.ClubSize[club] {
+			t.Log("Faulty Geminus club instantiation for", club)
+			t.Fail()
+		}
+	}
+
+	if gParams.ClubSize[Body] >= gParams.ClubSize[Head] {
+		t.Log("Club size should follow each case length")
+		t.Fail()
+	}
+
+	for i := 0; i < 200; i++ {
+		addr := fmt.Sprintf("10.40.%d.%d", i/256, i%256)
+		g.SetState(addr)
+
+		haddr := Addressing.NewAddress(addr, true)
+		for _, cd := range gParams.Cases {
+			inClub := g.SearchState(cd.Name, addr) != nil
+			if inClub != (cd.Of(g.Addr) == cd.Of(haddr)) {
+				t.Log("Peer club membership does not follow its", cd.Name, "case")
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestInvalidCases(t *testing.T) {
+	invalid := [][]CaseDefinition{
+		{},
+		{{Name: Head, Offset: 0, Length: 0}},
+		{{Name: Head, Offset: 158, Length: 3}},
+		{{Name: Head, Offset: -200, Length: 3}},
+		{{Name: Head, Offset: 0, Length: 3}, {Name: Head, Offset: -3, Length: 3}},
+		{{Name: Head, Offset: 0, Length: 65}},
+	}
+
+	for _, cases := range invalid {
+		g := NewGeminus("10.10.210.21", NewGeminiConfigWithCases(6000, 160, cases))
+		if err := g.Init(); err == nil {
+			t.Log("Invalid case definitions were accepted", cases)
+			t.Fail()
+		}
+	}
+}
+
+func TestReversedCases(t *testing.T) {
+	gParams := NewGeminiConfigWithCases(6000, 160, HeadTailReversedCases(3, 3))
+	g := NewGeminus("10.10.210.21", gParams)
+
+	g.Init()
+
+	for i := 0; i < 200; i++ {
+		addr := fmt.Sprintf("10.50.%d.%d", i/256, i%256)
+		g.SetState(addr)
+
+		haddr := Addressing.NewAddress(addr, true)
+
+		inReversedHead := g.SearchState(ReversedHead, addr) != nil
+		if inReversedHead != (haddr.Suffix(3) == g.Addr.Prefix(3)) {
+			t.Log("Reversed head club should hold the peers whose tail is our head")
+			t.Fail()
+		}
+
+		inReversedTail := g.SearchState(ReversedTail, addr) != nil
+		if inReversedTail != (haddr.Prefix(3) == g.Addr.Suffix(3)) {
+			t.Log("Reversed tail club should hold the peers whose head is our tail")
+			t.Fail()
+		}
+	}
+}
+
+func TestRouteAcrossCases(t *testing.T) {
+	gParams := NewGeminiConfigWithCases(6000, 160, HeadBodyTailCases(160, 3, 4, 3))
+	g := NewGeminus("10.10.210.21", gParams)
+
+	g.Init()
+
+	var forwarder string
+	for i := 0; forwarder == ""; i++ {
+		addr := fmt.Sprintf("10.60.%d.%d", i/256, i%256)
+		haddr := Addressing.NewAddress(addr, true)
+		inBody, _ := g.HaveSameClub(Body, g.Addr, haddr)
+		inHead, _ := g.HaveSameClub(Head, g.Addr, haddr)
+		inTail, _ := g.HaveSameClub(Tail, g.Addr, haddr)
+		if inBody && !inHead && !inTail {
+			forwarder = addr
+		}
+	}
+	g.SetState(forwarder)
+
+	hforwarder := Addressing.NewAddress(forwarder, true)
+
+	var destination string
+	for i := 0; destination == ""; i++ {
+		addr := fmt.Sprintf("10.70.%d.%d", i/256, i%256)
+		haddr := Addressing.NewAddress(addr, true)
+		if haddr.Prefix(3) == hforwarder.Prefix(3) && addr != forwarder {
+			destination = addr
+		}
+	}
+
+	foundAddr, status := g.Route(destination)
+	if status != BootForward || foundAddr.GetRaw() != forwarder {
+		t.Log("Destination head should be reached through the body club", status)
+		t.Fail()
+	}
+}
@@ -0,0 +1,69 @@
+package gemini
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// Run with -race, the assertions only catch the grossest failures.
+func TestConcurrentSetStateAndRoute(t *testing.T) {
+	g := NewGeminus("10.10.210.21", NewGeminiConfig(6000, 160, 3, 3))
+	g.Init()
+
+	var wg sync.WaitGroup
+
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 250; i++ {
+				g.SetState(fmt.Sprintf("10.%d.%d.%d", 90+w, i/256, i%256))
+			}
+		}(w)
+	}
+
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func(r int) {
+			defer wg.Done()
+			for i := 0; i < 250; i++ {
+				destination := fmt.Sprintf("10.%d.%d.%d", 90+r, i%256, i/256)
+				if foundAddr, status := g.Route(destination); foundAddr == nil && status != Undefined {
+					t.Error("Route returned no address without an Undefined status")
+				}
+				g.GetState()
+				g.ClosestPeers(destination, 3)
+				g.SearchState(Hat, destination)
+			}
+		}(r)
+	}
+
+	wg.Wait()
+
+	state := g.GetState()
+	hat, _ := g.GetClub(Hat)
+	boot, _ := g.GetClub(Boot)
+
+	if len(state) == 0 || len(hat)+len(boot) < len(state) {
+		t.Log("Concurrent writes got lost", len(state), len(hat), len(boot))
+		t.Fail()
+	}
+
+	expected := 0
+	for w := 0; w < 4; w++ {
+		for i := 0; i < 250; i++ {
+			addr := fmt.Sprintf("10.%d.%d.%d", 90+w, i/256, i%256)
+			inHat, _ := g.BelongsInClub(Hat, addr)
+			inBoot, _ := g.BelongsInClub(Boot, addr)
+			if inHat || inBoot {
+				expected++
+			}
+		}
+	}
+
+	if len(state) != expected {
+		t.Log("Concurrent writes got lost", len(state), expected)
+		t.Fail()
+	}
+}
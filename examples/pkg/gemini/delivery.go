@@ -0,0 +1,293 @@
+package gemini
+
+import (
+	"context"
+	"errors"
+	Addressing "gemelos/pkg/addressing"
+	Peer "gemelos/pkg/peer"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type DeliveryFailure string
+
+const (
+	TTLExceeded     DeliveryFailure = "TTLExceeded"
+	NoRoute                         = "NoRoute"
+	PeerUnreachable                 = "PeerUnreachable"
+	RoutingLoop                     = "RoutingLoop"
+)
+
+// delivered is the outcome a destination reports back.
+const delivered DeliveryFailure = "Delivered"
+
+// forwardAttempts is how many next hops a node tries before it reports a
+// delivery as PeerUnreachable.
+const forwardAttempts = 3
+
+// routeMemory is how long a node remembers the routes it forwarded, to
+// notice one coming back to it.
+const routeMemory = time.Minute
+
+var ErrNoTransport = errors.New("Geminus has no transport")
+
+type (
+	// DeliveryAck is the destination confirming it got the payload.
+	DeliveryAck struct {
+		Destination string
+		Hops        int
+	}
+
+	// DeliveryError tells why a delivery stopped and which node gave up on
+	// it, after how many hops.
+	DeliveryError struct {
+		Reason DeliveryFailure
+		Hop    string
+		Hops   int
+	}
+
+	// deliveries tracks the deliveries and requests a node originated,
+	// waiting for their outcome by nonce, and the routes it forwarded.
+	deliveries struct {
+		mu      sync.Mutex
+		pending map[uint64]chan *Peer.Message
+		seen    map[string]time.Time
+	}
+)
+
+func (e *DeliveryError) Error() string {
+	return "Delivery failed at " + e.Hop + " after " + strconv.Itoa(e.Hops) + " hop(s): " + string(e.Reason)
+}
+
+// Deliver sends payload to destination hop by hop: every node on the way
+// picks the next one with Route, until the destination acknowledges it or a
+// node reports a DeliveryError. Serve has to be running for the outcome to
+// come back.
+func (g *Geminus) Deliver(ctx context.Context, destination string, payload []byte) (*DeliveryAck, error) {
+	if g.Transport == nil {
+		return nil, ErrNoTransport
+	}
+
+	m := Peer.NewMessage(Peer.Forward, g.Addr.GetRaw())
+	m.Destination = destination
+	m.Payload = payload
+	m.TTL = g.ttl()
+	g.sign(m)
+
+	outcome := g.deliveries.expect(m.Nonce)
+	defer g.deliveries.done(m.Nonce)
+
+	g.forward(m)
+
+	select {
+	case report := <-outcome:
+		if reason := DeliveryFailure(report.Payload); reason != delivered {
+			return nil, &DeliveryError{
+				Reason: reason,
+				Hop:    report.Sender,
+				Hops:   int(report.Hops),
+			}
+		}
+		return &DeliveryAck{Destination: destination, Hops: int(report.Hops)}, nil
+
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Serve handles the messages coming in through the transport until ctx is
+// cancelled or the transport closes.
+func (g *Geminus) Serve(ctx context.Context) error {
+	if g.Transport == nil {
+		return ErrNoTransport
+	}
+
+	for {
+		select {
+		case envelope, open := <-g.Transport.Receive():
+			if !open {
+				return Peer.ErrClosed
+			}
+			g.handle(ctx, envelope)
+
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// handle drops anything that does not decode, and messages claiming an
+// identity they are not signed by.
+func (g *Geminus) handle(ctx context.Context, envelope Peer.Envelope) {
+	m, err := Peer.Decode(envelope.Payload)
+	if err != nil {
+		return
+	}
+
+	if _, signed := Addressing.PublicKeyOf(m.Sender); signed && !m.Verify() {
+		return
+	}
+
+	switch m.Type {
+	case Peer.Forward:
+		g.forward(m)
+	case Peer.RouteRequest:
+		g.answerRoute(m)
+	case Peer.Join:
+		g.answerJoin(m)
+	case Peer.ClubStateRequest:
+		g.answerClubState(m)
+	case Peer.Leave:
+		g.answerLeave(envelope, m)
+	case Peer.Ping:
+		g.answerPing(ctx, m)
+	case Peer.Broadcast:
+		g.answerBroadcast(m)
+	case Peer.Deliver, Peer.RouteResponse, Peer.ClubStateResponse, Peer.Pong:
+		g.deliveries.report(m)
+	}
+}
+
+// forward hands m to the application if we are its destination, and to
+// the next hop otherwise, reporting to the sender whatever stops it. A copy
+// the destination already got is only reported delivered again, so the
+// application gets every delivery once.
+func (g *Geminus) forward(m *Peer.Message) {
+	first := g.deliveries.visit(m)
+
+	if isAddr(g.newAddress(m.Destination), g.Addr) {
+		if first && g.OnDeliver != nil {
+			g.OnDeliver(m.Sender, m.Payload)
+		}
+		g.report(m, delivered)
+		return
+	}
+
+	if !first {
+		g.report(m, RoutingLoop)
+		return
+	}
+
+	if m.TTL == 0 {
+		g.report(m, TTLExceeded)
+		return
+	}
+
+	hop := *m
+	hop.TTL--
+	hop.Hops++
+	payload := Peer.Encode(&hop)
+
+	// a next hop that turns out to be gone is dropped from our clubs and
+	// the next best one is tried
+	avoid := make([]string, 0, forwardAttempts)
+	for attempt := 0; attempt < forwardAttempts; attempt++ {
+		next, _ := g.RouteAvoiding(m.Destination, avoid...)
+		if next == nil {
+			if attempt == 0 {
+				g.report(m, NoRoute)
+			} else {
+				g.report(m, PeerUnreachable)
+			}
+			return
+		}
+
+		err := g.Transport.Send(Addressing.Endpoint(next.GetRaw()), payload)
+		if err == nil {
+			return
+		}
+		if err == Peer.ErrUnreachable {
+			g.Evict(next.GetRaw())
+		}
+		avoid = append(avoid, next.GetRaw())
+	}
+
+	g.report(m, PeerUnreachable)
+}
+
+// report tells the sender of m how its delivery went, straight to it
+// rather than back along the route.
+func (g *Geminus) report(m *Peer.Message, outcome DeliveryFailure) {
+	r := Peer.NewMessage(Peer.Deliver, g.Addr.GetRaw())
+	r.Nonce = m.Nonce
+	r.Hops = m.Hops
+	r.Destination = m.Destination
+	r.Payload = []byte(outcome)
+	g.sign(r)
+
+	if isAddr(g.newAddress(m.Sender), g.Addr) {
+		g.deliveries.report(r)
+		return
+	}
+
+	g.Transport.Send(Addressing.Endpoint(m.Sender), Peer.Encode(r))
+}
+
+func (g *Geminus) sign(m *Peer.Message) {
+	if g.Identity != nil {
+		m.Sign(g.Identity)
+	}
+}
+
+func (d *deliveries) expect(nonce uint64) chan *Peer.Message {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.pending == nil {
+		d.pending = make(map[uint64]chan *Peer.Message)
+	}
+
+	outcome := make(chan *Peer.Message, 1)
+	d.pending[nonce] = outcome
+	return outcome
+}
+
+func (d *deliveries) done(nonce uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.pending, nonce)
+}
+
+// report passes the first outcome of a pending delivery on, later ones and
+// outcomes nobody waits for are dropped.
+func (d *deliveries) report(r *Peer.Message) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if outcome, ok := d.pending[r.Nonce]; ok {
+		select {
+		case outcome <- r:
+		default:
+		}
+	}
+}
+
+// visit remembers the route of m and tells whether it is the first time
+// it comes by.
+func (d *deliveries) visit(m *Peer.Message) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+
+	if d.seen == nil {
+		d.seen = make(map[string]time.Time)
+	}
+	if len(d.seen) > 1024 {
+		for route, at := range d.seen {
+			if now.Sub(at) > routeMemory {
+				delete(d.seen, route)
+			}
+		}
+	}
+
+	route := m.Sender + "/" + strconv.FormatUint(m.Nonce, 16)
+	if at, ok := d.seen[route]; ok && now.Sub(at) <= routeMemory {
+		return false
+	}
+	d.seen[route] = now
+
+	return true
+}
@@ -0,0 +1,183 @@
+package gemini
+
+import (
+	"context"
+	"fmt"
+	Addressing "gemelos/pkg/addressing"
+	Peer "gemelos/pkg/peer"
+	"testing"
+	"time"
+)
+
+// newMemoryNodes starts count nodes on one memory network, each knowing
+// every other node its clubs accept.
+func newMemoryNodes(ctx context.Context, count int, newConfig func() *GeminiConfig) []*Geminus {
+	network := Peer.NewMemoryNetwork()
+	nodes := make([]*Geminus, count)
+
+	for i := range nodes {
+		addr := fmt.Sprintf("10.80.%d.%d", i/256, i%256)
+		transport := network.NewTransport()
+		transport.Listen(addr)
+
+		nodes[i] = NewGeminus(addr, newConfig())
+		nodes[i].Init()
+		nodes[i].Transport = transport
+	}
+
+	for _, g := range nodes {
+		for _, other := range nodes {
+			if g != other {
+				g.SetState(other.Addr.GetRaw())
+			}
+		}
+		go g.Serve(ctx)
+	}
+
+	return nodes
+}
+
+func TestDeliver(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	nodes := newMemoryNodes(ctx, 300, func() *GeminiConfig { return NewGeminiConfig(300, 160, 3, 3) })
+
+	received := make(chan string, 1)
+	destination := nodes[len(nodes)-1]
+	destination.OnDeliver = func(sender string, payload []byte) { received <- string(payload) }
+
+	ack, err := nodes[0].Deliver(ctx, destination.Addr.GetRaw(), []byte("gemini"))
+	if err != nil || ack.Hops < 1 {
+		t.Fatal("Faulty multi-hop delivery", err)
+	}
+
+	if payload := <-received; payload != "gemini" {
+		t.Log("Destination got the wrong payload", payload)
+		t.Fail()
+	}
+
+	failures := 0
+	for i := 1; i < 100; i++ {
+		ack, err := nodes[i].Deliver(ctx, nodes[(i*7)%len(nodes)].Addr.GetRaw(), nil)
+		if err != nil {
+			if _, typed := err.(*DeliveryError); !typed {
+				t.Fatal("Delivery failures should be DeliveryErrors", err)
+			}
+			failures++
+			continue
+		}
+		if ack.Hops > int(Peer.DefaultTTL) {
+			t.Log("Delivery took more hops than its TTL allows", ack.Hops)
+			t.Fail()
+		}
+	}
+
+	if failures > 5 {
+		t.Log("Too many deliveries failed in a fully seeded network", failures)
+		t.Fail()
+	}
+
+	if ack, err := nodes[0].Deliver(ctx, nodes[0].Addr.GetRaw(), nil); err != nil || ack.Hops != 0 {
+		t.Log("Delivering to ourselves should not take any hop", err)
+		t.Fail()
+	}
+}
+
+func TestDeliverOnce(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	network := Peer.NewMemoryNetwork()
+	sender := network.NewTransport()
+	sender.Listen("10.90.1.1")
+
+	transport := network.NewTransport()
+	transport.Listen("10.90.1.2")
+	destination := NewGeminus("10.90.1.2", NewGeminiConfig(6000, 160, 3, 3))
+	destination.Init()
+	destination.Transport = transport
+
+	calls := 0
+	destination.OnDeliver = func(string, []byte) { calls++ }
+	go destination.Serve(ctx)
+
+	m := Peer.NewMessage(Peer.Forward, "10.90.1.1")
+	m.Destination = destination.Addr.GetRaw()
+	m.Payload = []byte("gemini")
+	sender.Send("10.90.1.2", Peer.Encode(m))
+	sender.Send("10.90.1.2", Peer.Encode(m))
+
+	for i := 0; i < 2; i++ {
+		select {
+		case envelope := <-sender.Receive():
+			report, err := Peer.Decode(envelope.Payload)
+			if err != nil || report.Type != Peer.Deliver || DeliveryFailure(report.Payload) != delivered {
+				t.Log("Every copy should be reported delivered", err)
+				t.Fail()
+			}
+		case <-ctx.Done():
+			t.Fatal("The destination did not report the delivery")
+		}
+	}
+
+	if calls != 1 {
+		t.Log("The application should get a delivery once", calls)
+		t.Fail()
+	}
+}
+
+// fixedStrategy always forwards to the same peer.
+type fixedStrategy struct {
+	next Addressing.Addr
+}
+
+func (s fixedStrategy) Next(*ClubState, Addressing.Addr) (Addressing.Addr, RoutingStatus) {
+	return s.next, RandomForward
+}
+
+func TestDeliveryFailures(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	network := Peer.NewMemoryNetwork()
+	newNode := func(addr string) *Geminus {
+		transport := network.NewTransport()
+		transport.Listen(addr)
+
+		g := NewGeminus(addr, NewGeminiConfig(6000, 160, 3, 3))
+		g.Init()
+		g.Transport = transport
+		go g.Serve(ctx)
+		return g
+	}
+
+	a, b, c := newNode("10.90.0.1"), newNode("10.90.0.2"), newNode("10.90.0.3")
+
+	expectFailure := func(g *Geminus, reason DeliveryFailure, hop *Geminus) {
+		_, err := g.Deliver(ctx, "41.210.42.31", nil)
+		failure, typed := err.(*DeliveryError)
+		if !typed || failure.Reason != reason || failure.Hop != hop.Addr.GetRaw() {
+			t.Log("Expected", reason, "at", hop.Addr.GetRaw(), "got", err)
+			t.Fail()
+		}
+	}
+
+	expectFailure(a, NoRoute, a)
+
+	a.Params.Strategy = fixedStrategy{b.Addr}
+	b.Params.Strategy = fixedStrategy{a.Addr}
+	expectFailure(a, RoutingLoop, a)
+
+	b.Params.Strategy = fixedStrategy{c.Addr}
+	c.Params.Strategy = fixedStrategy{Addressing.NewAddress("10.90.0.4", true)}
+	expectFailure(a, PeerUnreachable, c)
+
+	a.Params.TTL = 1
+	expectFailure(a, TTLExceeded, b)
+
+	if _, err := NewGeminus("10.90.0.5", NewGeminiConfig(6000, 160, 3, 3)).Deliver(ctx, "41.210.42.31", nil); err != ErrNoTransport {
+		t.Log("Delivering without a transport should fail", err)
+		t.Fail()
+	}
+}
@@ -0,0 +1,92 @@
+package gemini
+
+import (
+	Addressing "gemelos/pkg/addressing"
+	"time"
+)
+
+type (
+	// PeerInfo is what a node remembers about a peer besides its address.
+	// It is shared by every club the peer is in.
+	PeerInfo struct {
+		LastSeen time.Time
+		Score    float64
+		// Scored tells a peer SetScore was called for from one that merely
+		// has not been scored yet.
+		Scored bool
+		// Liveness is what the failure detector makes of the peer, since
+		// when is kept once it is suspected.
+		Liveness     Liveness
+		SuspectSince time.Time
+	}
+
+	// EvictionPolicy picks who leaves a full club. Candidates are the club
+	// members followed by the newcomer, peers holds their PeerInfo keyed by
+	// hashed id. Returning the newcomer's index turns it away.
+	EvictionPolicy interface {
+		Victim(state *ClubState, club Club, candidates []Addressing.Addr, peers map[string]PeerInfo) int
+	}
+
+	// LeastRecentlySeen evicts the peer we heard from the longest time ago.
+	LeastRecentlySeen struct{}
+
+	// FurthestPeer evicts the peer furthest from the node on the ring, so
+	// clubs keep the neighbours routing converges through.
+	FurthestPeer struct{}
+
+	// LowestScore evicts the member with the lowest score. A newcomer that
+	// was not scored yet counts as scoring the club mean, so a club of well
+	// scored members still admits newcomers; it is turned away only when it
+	// scores below every member.
+	LowestScore struct{}
+)
+
+var DefaultEvictionPolicy EvictionPolicy = LeastRecentlySeen{}
+
+func (LeastRecentlySeen) Victim(s *ClubState, club Club, candidates []Addressing.Addr, peers map[string]PeerInfo) int {
+	victim := 0
+	for i, v := range candidates {
+		if peers[string(v.GetHash())].LastSeen.Before(peers[string(candidates[victim].GetHash())].LastSeen) {
+			victim = i
+		}
+	}
+	return victim
+}
+
+func (FurthestPeer) Victim(s *ClubState, club Club, candidates []Addressing.Addr, peers map[string]PeerInfo) int {
+	victim := 0
+	furthest := s.Params.Ring.ShortestDistance(s.Self.GetHash(), candidates[0].GetHash())
+	for i, v := range candidates {
+		if d := s.Params.Ring.ShortestDistance(s.Self.GetHash(), v.GetHash()); d.Cmp(furthest) > 0 {
+			victim, furthest = i, d
+		}
+	}
+	return victim
+}
+
+func (LowestScore) Victim(s *ClubState, club Club, candidates []Addressing.Addr, peers map[string]PeerInfo) int {
+	members := candidates[:len(candidates)-1]
+	if len(members) == 0 {
+		return 0
+	}
+
+	victim, total := 0, 0.0
+	for i, v := range members {
+		score := peers[string(v.GetHash())].Score
+		total += score
+		if score < peers[string(members[victim].GetHash())].Score {
+			victim = i
+		}
+	}
+
+	newcomer := peers[string(candidates[len(members)].GetHash())]
+	score := newcomer.Score
+	if !newcomer.Scored {
+		score = total / float64(len(members))
+	}
+
+	if score < peers[string(members[victim].GetHash())].Score {
+		return len(members)
+	}
+	return victim
+}
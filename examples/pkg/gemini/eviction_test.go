@@ -0,0 +1,53 @@
+package gemini
+
+import (
+	Addressing "gemelos/pkg/addressing"
+	"testing"
+	"time"
+)
+
+func TestEvictionPolicies(t *testing.T) {
+	self := id(0x0001)
+	near := id(0x0101)
+	far := id(0x7f01)
+	newcomer := id(0x0201)
+
+	s := newClubState(DefaultCases(3, 3), self, near, far)
+	candidates := []Addressing.Addr{near, far, newcomer}
+
+	now := time.Now()
+	peers := map[string]PeerInfo{
+		string(near.GetHash()):     {LastSeen: now.Add(-time.Hour), Score: 2},
+		string(far.GetHash()):      {LastSeen: now.Add(-time.Minute), Score: 1},
+		string(newcomer.GetHash()): {LastSeen: now, Score: 3},
+	}
+
+	if victim := (LeastRecentlySeen{}).Victim(s, Hat, candidates, peers); victim != 0 {
+		t.Log("Least recently seen peer should be evicted", victim)
+		t.Fail()
+	}
+
+	if victim := (FurthestPeer{}).Victim(s, Hat, candidates, peers); victim != 1 {
+		t.Log("Furthest peer on the ring should be evicted", victim)
+		t.Fail()
+	}
+
+	if victim := (LowestScore{}).Victim(s, Hat, candidates, peers); victim != 1 {
+		t.Log("Lowest scored peer should be evicted", victim)
+		t.Fail()
+	}
+
+	peers[string(newcomer.GetHash())] = PeerInfo{LastSeen: now, Score: 0, Scored: true}
+
+	if victim := (LowestScore{}).Victim(s, Hat, candidates, peers); victim != 2 {
+		t.Log("A newcomer scoring lower than every member should be turned away", victim)
+		t.Fail()
+	}
+
+	peers[string(newcomer.GetHash())] = PeerInfo{LastSeen: now}
+
+	if victim := (LowestScore{}).Victim(s, Hat, candidates, peers); victim != 1 {
+		t.Log("A newcomer not scored yet should get in when every member scores positive", victim)
+		t.Fail()
+	}
+}
@@ -4,9 +4,16 @@ import (
 	bytes "bytes"
 	"errors"
 	Addressing "gemelos/pkg/addressing"
+	Peer "gemelos/pkg/peer"
 	Ring "gemelos/pkg/ring"
 	Tools "gemelos/pkg/tools"
-	"math"
+	"math/big"
+	"math/rand"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type Club string
@@ -20,10 +27,11 @@ const (
 type RoutingStatus string
 
 const (
-	HatRoute      RoutingStatus = "HatRoute"
-	BootForward                 = "BootForward"
-	RandomForward               = "RandomForward"
-	Undefined                   = "Undefined"
+	HatRoute          RoutingStatus = "HatRoute"
+	HatClosestForward               = "HatClosestForward"
+	BootForward                     = "BootForward"
+	RandomForward                   = "RandomForward"
+	Undefined                       = "Undefined"
 )
 
 type (
@@ -32,127 +40,522 @@ type (
 		GetState() []Addressing.Addr
 		SetState(addr string) (Club, error)
 		AddInClub(Club, Addressing.Addr) error
+		RemoveFromClub(Club, Addressing.Addr) error
+		Evict(string) error
 		GetClub(Club) ([]Addressing.Addr, error)
 		HaveSameClub(Club, Addressing.Addr, Addressing.Addr) (bool, error)
 		BelongsInClub(Club, string) (bool, error)
 		GetAddrDistance(string) int
+		ClockwiseDistance(string) *big.Int
+		CounterClockwiseDistance(string) *big.Int
+		Distance(string) *big.Int
+		ClosestPeers(string, int) []Addressing.Addr
 		Route(string) (Addressing.Addr, RoutingStatus)
 		SearchState(Club, string) Addressing.Addr
 	}
 
 	GeminiConfig struct {
-		Ring       *Ring.GeminiRing
-		AddrLength int
-		HatLength  int
-		BootLength int
-		ClubSize   map[Club]int
+		Ring            *Ring.GeminiRing
+		Hasher          Addressing.Hasher
+		Salter          Addressing.Salter
+		NetworkCapacity int
+		AddrLength      int
+		HatLength       int
+		BootLength      int
+		Cases           []CaseDefinition
+		ClubSize        map[Club]int
+		Strategy        RoutingStrategy
+		Eviction        EvictionPolicy
+		// TTL is how many hops a delivery or a lookup may take,
+		// Peer.DefaultTTL if unset.
+		TTL uint32
+		// RequestTimeout is how long to wait for a peer to answer,
+		// DefaultRequestTimeout if unset.
+		RequestTimeout time.Duration
+		// TombstoneTTL is how long a peer that left is kept out of our
+		// clubs, DefaultTombstoneTTL if unset.
+		TombstoneTTL time.Duration
+		// ProbeInterval, SuspicionTimeout, IndirectProbes and
+		// IndirectRelays, how many indirect probes a node relays for others
+		// at once, tune the failure detector, the Default ones if unset.
+		ProbeInterval    time.Duration
+		SuspicionTimeout time.Duration
+		IndirectProbes   int
+		IndirectRelays   int
+		// BroadcastRedundancy is how many members of a club a node relays
+		// a broadcast to, every member if unset. Relaying to fewer trades
+		// coverage for traffic.
+		BroadcastRedundancy int
+		// FallbackNeighbors is how many neighbors a lonely island adopts,
+		// DefaultFallbackNeighbors if unset.
+		FallbackNeighbors int
+		// Source is where the random choices of the node come from: random
+		// forwards, probe order and broadcast relays. It has to be safe for
+		// concurrent use, see Tools.NewSource; a time seeded one if unset.
+		// Message nonces stay out of it: they come from crypto/rand, so a
+		// reply cannot be forged by guessing the nonce it has to echo.
+		Source rand.Source
 	}
 
+	// Geminus is safe for concurrent use once Init returned. Writers
+	// serialize on a mutex and never modify a club in place: every change
+	// publishes fresh club slices as an immutable ClubState, which readers
+	// such as Route load without locking. Clubs always points at the latest
+	// published clubs; read it directly only when no writer is running.
+	//
+	// A club never holds more than its ClubSize members (none means no
+	// bound), the configured EvictionPolicy picks who leaves once it is full.
+	//
+	// Transport is how the node reaches the endpoints of its peers; Deliver
+	// and Serve need one. OnDeliver is handed the payloads addressed to the
+	// node, OnBroadcast the broadcasts of other nodes.
+	//
+	// Routing skips the members the failure detector suspects or declared
+	// dead, see Probe.
 	Geminus struct {
-		Params *GeminiConfig
-		Addr   Addressing.Addr
-		Clubs  map[Club][]Addressing.Addr
+		Params      *GeminiConfig
+		Addr        Addressing.Addr
+		Identity    *Addressing.NodeIdentity
+		Clubs       map[Club][]Addressing.Addr
+		Transport   Peer.Transport
+		OnDeliver   func(sender string, payload []byte)
+		OnBroadcast func(origin string, payload []byte)
+
+		mu         sync.Mutex
+		snapshot   atomic.Value
+		peers      map[string]PeerInfo
+		deliveries deliveries
+		newcomers  newcomers
+		tombstones tombstones
+		detector   detector
 	}
 )
 
+var _ Gemini = (*Geminus)(nil)
+
+// NewGeminiConfig hashes addresses with Addressing.DefaultHasher, so the
+// network order is expected to match its digest size.
 func NewGeminiConfig(networkCapacity, networkOrder, hatLength, bootLength int) *GeminiConfig {
-	return &GeminiConfig{
-		Ring:       Ring.NewGeminiRing(networkOrder),
-		AddrLength: networkOrder,
-		HatLength:  hatLength,
-		BootLength: bootLength,
-		ClubSize: map[Club]int{
-			Hat:  int(float64(networkCapacity) / math.Pow(2, float64(hatLength))),
-			Boot: int(float64(networkCapacity) / math.Pow(2, float64(hatLength))),
-		},
+	gParams := NewGeminiConfigWithCases(networkCapacity, networkOrder, DefaultCases(hatLength, bootLength))
+	gParams.HatLength = hatLength
+	gParams.BootLength = bootLength
+	return gParams
+}
+
+// NewGeminiConfigWithCases keeps one club per case definition. The first
+// case is the one routing converges on, the others are used to forward
+// towards it. Every club is bounded by the MaxClubSize of its case.
+func NewGeminiConfigWithCases(networkCapacity, networkOrder int, cases []CaseDefinition) *GeminiConfig {
+	gParams := &GeminiConfig{
+		Ring:            Ring.NewGeminiRing(networkOrder),
+		Hasher:          Addressing.DefaultHasher,
+		Salter:          Addressing.DefaultSalter,
+		NetworkCapacity: networkCapacity,
+		AddrLength:      networkOrder,
+		Cases:           cases,
+		ClubSize:        make(map[Club]int, len(cases)),
+	}
+
+	for _, cd := range cases {
+		gParams.ClubSize[cd.Name] = gParams.MaxClubSize(cd.Name)
+	}
+
+	return gParams
+}
+
+// NewGeminiConfigWithHasher derives the network order, and with it the
+// ring and address length, from the digest size of the given hasher.
+func NewGeminiConfigWithHasher(networkCapacity int, hasher Addressing.Hasher, hatLength, bootLength int) *GeminiConfig {
+	gParams := NewGeminiConfig(networkCapacity, hasher.Size()*8, hatLength, bootLength)
+	gParams.Hasher = hasher
+	return gParams
+}
+
+// defaultSource serves the configs that were not given a Source.
+var defaultSource = Tools.NewSource(time.Now().UnixNano())
+
+// random draws from the configured Source.
+func (gc *GeminiConfig) random() *rand.Rand {
+	if gc.Source != nil {
+		return rand.New(gc.Source)
 	}
+	return rand.New(defaultSource)
 }
 
 func NewGeminus(addr string, gParams *GeminiConfig) *Geminus {
-	gAddr := Addressing.NewAddress(addr)
+	gAddr := Addressing.NewDerivedAddress(addr, gParams.Hasher, gParams.Salter)
 
-	return &Geminus{
+	clubs := make(map[Club][]Addressing.Addr, len(gParams.Cases))
+	for _, cd := range gParams.Cases {
+		clubs[cd.Name] = make([]Addressing.Addr, 0, gParams.ClubSize[cd.Name])
+	}
+
+	g := &Geminus{
 		Params: gParams,
 		Addr:   gAddr,
-		Clubs: map[Club][]Addressing.Addr{
-			Hat:  make([]Addressing.Addr, 0, gParams.ClubSize[Hat]),
-			Boot: make([]Addressing.Addr, 0, gParams.ClubSize[Boot]),
-		},
+		peers:  make(map[string]PeerInfo),
 	}
+	g.publish(clubs)
+
+	return g
+}
+
+// newAddress hashes a raw address with the hasher and salter the network
+// agreed on.
+func (g *Geminus) newAddress(addr string) Addressing.Addr {
+	return Addressing.NewDerivedAddress(addr, g.Params.Hasher, g.Params.Salter, true)
+}
+
+// NewGeminusFromIdentity places the node on the ring by its public key
+// and keeps the endpoint around for transports to reach it.
+func NewGeminusFromIdentity(identity *Addressing.NodeIdentity, endpoint string, gParams *GeminiConfig) *Geminus {
+	g := NewGeminus(identity.Raw(endpoint), gParams)
+	g.Identity = identity
+	return g
 }
 
 func (g *Geminus) Init() error {
 	g.Addr.Hash()
-	if len(g.Addr.GetHash()) != g.Params.AddrLength/8 {
+	if len(g.Addr.GetHash()) != g.Params.Hasher.Size() || g.Params.AddrLength != g.Params.Hasher.Size()*8 {
 		return errors.New("Wrong Gemini Address Length Param or Faulty Hash Function")
 	}
-	return nil
+
+	return g.Params.ValidateCases()
 }
 
+// GetState returns every known peer once, in case definition order and
+// Neighbors last.
 func (g *Geminus) GetState() []Addressing.Addr {
-	return []Addressing.Addr(append(g.Clubs[Hat], g.Clubs[Boot]...))
+	state := make([]Addressing.Addr, 0)
+	seen := make(map[string]bool)
+
+	clubs := g.clubState().Clubs
+	for _, club := range g.Params.clubNames() {
+		for _, v := range clubs[club] {
+			if !seen[string(v.GetHash())] {
+				seen[string(v.GetHash())] = true
+				state = append(state, v)
+			}
+		}
+	}
+
+	return state
 }
 
+// SetState adds addr to every club it belongs in and returns the first of
+// them, in case definition order.
 func (g *Geminus) SetState(addr string) (Club, error) {
-	var club Club
+	var club Club = Unrecognized
 
-	if c, _ := g.BelongsInClub(Hat, addr); c {
-		club = Hat
-	} else if c, _ := g.BelongsInClub(Boot, addr); c {
-		club = Boot
-	} else {
-		return Unrecognized, errors.New("Unrecognized club/case")
+	haddr := g.newAddress(addr)
+	if g.tombstones.buried(haddr) {
+		return Unrecognized, ErrPeerLeft
+	}
+	if g.dead(haddr) {
+		return Unrecognized, ErrPeerDead
 	}
 
-	haddr := Addressing.NewAddress(addr, true)
+	for _, cd := range g.Params.Cases {
+		if c, _ := g.HaveSameClub(cd.Name, g.Addr, haddr); c {
+			g.AddInClub(cd.Name, haddr)
+			if club == Unrecognized {
+				club = cd.Name
+			}
+		}
+	}
 
-	g.AddInClub(club, haddr)
+	if club == Unrecognized {
+		return Unrecognized, errors.New("Unrecognized club/case")
+	}
 
 	return club, nil
 }
 
 func (g *Geminus) GetClub(club Club) ([]Addressing.Addr, error) {
-	if club != Hat && club != Boot {
+	if !g.Params.HasClub(club) {
 		return nil, errors.New("Unrecognized club/case")
 	}
-	return g.Clubs[club], nil
+	return g.clubState().Clubs[club], nil
 }
 
+// AddInClub adds v to a club, or refreshes it if a peer with the same id
+// is already there. Adding counts as having seen the peer.
 func (g *Geminus) AddInClub(club Club, v Addressing.Addr) error {
-	if club != Hat && club != Boot {
+	if !g.Params.HasClub(club) {
 		return errors.New("Unrecognized club/case")
 	}
+	if g.tombstones.buried(v) {
+		return ErrPeerLeft
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	id := string(v.GetHash())
+	info := g.peers[id]
+	if info.Liveness == Dead {
+		return ErrPeerDead
+	}
+	info.LastSeen = time.Now()
+	if info.Liveness == "" {
+		info.Liveness = Alive
+	}
+	g.peers[id] = info
+
+	clubs := g.copyClubs()
+	members := clubs[club]
+
+	if i := indexOf(members, v); i >= 0 {
+		clubs[club] = append(append(append(make([]Addressing.Addr, 0, len(members)), members[:i]...), v), members[i+1:]...)
+		g.publish(clubs)
+		return nil
+	}
 
-	g.Clubs[club] = append(g.Clubs[club], v)
+	if size := g.Params.ClubSize[club]; size > 0 && len(members) >= size {
+		candidates := append(append(make([]Addressing.Addr, 0, len(members)+1), members...), v)
+		state := &ClubState{Params: g.Params, Self: g.Addr, Clubs: g.Clubs}
+		victim := g.eviction().Victim(state, club, candidates, g.peers)
+
+		if victim < 0 || victim >= len(members) {
+			g.forget(v, clubs)
+			return errors.New("Club " + string(club) + " is full")
+		}
+
+		clubs[club] = append(append(make([]Addressing.Addr, 0, len(members)), members[:victim]...), candidates[victim+1:]...)
+		g.forget(members[victim], clubs)
+		g.publish(clubs)
+		return nil
+	}
+
+	clubs[club] = append(append(make([]Addressing.Addr, 0, len(members)+1), members...), v)
+	g.publish(clubs)
 
 	return nil
 }
 
-func (g *Geminus) HaveSameClub(club Club, haddrA Addressing.Addr, haddrB Addressing.Addr) (bool, error) {
-	var caseA, caseB []byte
+// AddError lists the peers AddAllInClub did not add, each with the error
+// AddInClub gave for it.
+type AddError struct {
+	Club  Club
+	Peers []Addressing.Addr
+	Errs  []error
+}
 
-	if club == Hat {
-		caseStart, caseEnd := 0, g.Params.HatLength-1
-		caseA = haddrA.GetBinaryHash()[caseStart:caseEnd]
-		caseB = haddrB.GetBinaryHash()[caseStart:caseEnd]
-	} else if club == Boot {
-		haddrALength, haddrBLength := haddrA.GetBinBitLength(), haddrB.GetBinBitLength()
+func (e *AddError) Error() string {
+	return strconv.Itoa(len(e.Errs)) + " peer(s) not added to club " + string(e.Club) + ", the first: " + e.Errs[0].Error()
+}
 
-		haddrACaseStart, haddrACaseEnd := (haddrALength-1)-g.Params.BootLength, (haddrALength - 1)
-		haddrBCaseStart, haddrBCaseEnd := (haddrBLength-1)-g.Params.BootLength, (haddrBLength - 1)
+func (e *AddError) add(v Addressing.Addr, err error) {
+	e.Peers = append(e.Peers, v)
+	e.Errs = append(e.Errs, err)
+}
 
-		caseA = haddrA.GetBinaryHash()[haddrACaseStart:haddrACaseEnd]
-		caseB = haddrB.GetBinaryHash()[haddrBCaseStart:haddrBCaseEnd]
-	} else {
-		return false, errors.New("Unrecognized club/case")
+// AddAllInClub adds several peers to a club, publishing the club once
+// rather than once per peer, which matters when seeding large clubs. Peers
+// already there are refreshed and replaced by the new address, as AddInClub
+// does. Whatever does not fit the club bound goes through AddInClub, and so
+// through eviction, one peer at a time. The peers left out are returned as
+// an AddError.
+func (g *Geminus) AddAllInClub(club Club, members []Addressing.Addr) error {
+	if !g.Params.HasClub(club) {
+		return errors.New("Unrecognized club/case")
+	}
+
+	failed := &AddError{Club: club}
+	overflow := make([]Addressing.Addr, 0)
+
+	g.mu.Lock()
+
+	clubs := g.copyClubs()
+	current := clubs[club]
+	known := make(map[string]int, len(current)+len(members))
+	for i, v := range current {
+		known[string(v.GetHash())] = i
+	}
+
+	added := append(make([]Addressing.Addr, 0, len(current)+len(members)), current...)
+	now := time.Now()
+	for _, v := range members {
+		id := string(v.GetHash())
+		if g.tombstones.buried(v) {
+			failed.add(v, ErrPeerLeft)
+			continue
+		}
+		if g.peers[id].Liveness == Dead {
+			failed.add(v, ErrPeerDead)
+			continue
+		}
+		i, isMember := known[id]
+		if size := g.Params.ClubSize[club]; !isMember && size > 0 && len(added) >= size {
+			overflow = append(overflow, v)
+			continue
+		}
+
+		info := g.peers[id]
+		info.LastSeen = now
+		if info.Liveness == "" {
+			info.Liveness = Alive
+		}
+		g.peers[id] = info
+
+		if isMember {
+			added[i] = v
+		} else {
+			known[id] = len(added)
+			added = append(added, v)
+		}
+	}
+
+	clubs[club] = added
+	g.publish(clubs)
+
+	g.mu.Unlock()
+
+	for _, v := range overflow {
+		if err := g.AddInClub(club, v); err != nil {
+			failed.add(v, err)
+		}
+	}
+
+	if len(failed.Errs) > 0 {
+		return failed
+	}
+	return nil
+}
+
+// RemoveFromClub drops v from a single club, the node forgets about it once
+// it is in none of them.
+func (g *Geminus) RemoveFromClub(club Club, v Addressing.Addr) error {
+	if !g.Params.HasClub(club) {
+		return errors.New("Unrecognized club/case")
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	members := g.Clubs[club]
+	i := indexOf(members, v)
+	if i < 0 {
+		return errors.New("Peer is not a member of club " + string(club))
+	}
+
+	clubs := g.copyClubs()
+	clubs[club] = append(append(make([]Addressing.Addr, 0, len(members)-1), members[:i]...), members[i+1:]...)
+	g.forget(v, clubs)
+	g.publish(clubs)
+
+	return nil
+}
+
+// Evict drops addr from every club it is in.
+func (g *Geminus) Evict(addr string) error {
+	haddr := g.newAddress(addr)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	clubs := g.copyClubs()
+	evicted := false
+	for club, members := range clubs {
+		if i := indexOf(members, haddr); i >= 0 {
+			clubs[club] = append(append(make([]Addressing.Addr, 0, len(members)-1), members[:i]...), members[i+1:]...)
+			evicted = true
+		}
+	}
+
+	if !evicted {
+		return errors.New("Unknown peer " + addr)
+	}
+
+	g.forget(haddr, clubs)
+	g.publish(clubs)
+
+	return nil
+}
+
+// Touch records that addr was just heard from.
+func (g *Geminus) Touch(addr string) {
+	g.updatePeer(addr, func(info *PeerInfo) { info.LastSeen = time.Now() })
+}
+
+// SetScore sets the score LowestScore evicts by.
+func (g *Geminus) SetScore(addr string, score float64) {
+	g.updatePeer(addr, func(info *PeerInfo) {
+		info.Score = score
+		info.Scored = true
+	})
+}
+
+// Peer returns what the node knows about a club member, or about a peer
+// the failure detector declared dead.
+func (g *Geminus) Peer(addr string) (PeerInfo, bool) {
+	haddr := g.newAddress(addr)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	info, ok := g.peers[string(haddr.GetHash())]
+	return info, ok
+}
+
+// updatePeer only touches peers that are in a club, so that the metadata
+// never outlives the membership, leaving alone the records of dead peers.
+func (g *Geminus) updatePeer(addr string, update func(*PeerInfo)) {
+	id := string(g.newAddress(addr).GetHash())
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if info, ok := g.peers[id]; ok && info.Liveness != Dead {
+		update(&info)
+		g.peers[id] = info
+	}
+}
+
+func (g *Geminus) eviction() EvictionPolicy {
+	if g.Params.Eviction == nil {
+		return DefaultEvictionPolicy
+	}
+	return g.Params.Eviction
+}
+
+// forget drops the metadata of v unless it is still in one of clubs;
+// callers hold g.mu.
+func (g *Geminus) forget(v Addressing.Addr, clubs map[Club][]Addressing.Addr) {
+	for _, members := range clubs {
+		if indexOf(members, v) >= 0 {
+			return
+		}
+	}
+	id := string(v.GetHash())
+	if info, ok := g.peers[id]; ok && info.Liveness != Alive {
+		defer g.publishUnhealthy()
+	}
+	delete(g.peers, id)
+}
+
+func indexOf(members []Addressing.Addr, v Addressing.Addr) int {
+	for i, m := range members {
+		if isAddr(m, v) {
+			return i
+		}
 	}
+	return -1
+}
 
-	return bytes.Compare(caseA, caseB) == 0, nil
+// HaveSameClub reports whether haddrB belongs in the club haddrA keeps for
+// the given case. Only reversed cases make the order of the two matter.
+func (g *Geminus) HaveSameClub(club Club, haddrA Addressing.Addr, haddrB Addressing.Addr) (bool, error) {
+	cd, ok := g.Params.Case(club)
+	if !ok {
+		return false, errors.New("Unrecognized club/case")
+	}
 
+	return cd.Of(haddrA) == cd.PeerCase(haddrB), nil
 }
 
 func (g *Geminus) BelongsInClub(club Club, addr string) (bool, error) {
-	haddr := Addressing.NewAddress(addr, true)
+	haddr := g.newAddress(addr)
 	return g.HaveSameClub(club, g.Addr, haddr)
 }
 
@@ -164,10 +567,10 @@ func (g *Geminus) SearchState(c Club, needle string) Addressing.Addr {
 		panic(err)
 	}
 
-	hneedle := Addressing.NewAddress(needle, true)
+	hneedle := g.newAddress(needle)
 
 	for _, v := range club {
-		if bytes.Compare(v.GetBinaryHash(), hneedle.GetBinaryHash()) == 0 {
+		if bytes.Equal(v.GetHash(), hneedle.GetHash()) {
 			item = v
 		}
 	}
@@ -175,54 +578,141 @@ func (g *Geminus) SearchState(c Club, needle string) Addressing.Addr {
 	return item
 }
 
+// GetAddrDistance returns the bit length of the shortest ring distance
+// between the node and addr, a logarithmic distance that always fits an int.
+func (g *Geminus) GetAddrDistance(addr string) int {
+	return g.Distance(addr).BitLen()
+}
+
+func (g *Geminus) ClockwiseDistance(addr string) *big.Int {
+	haddr := g.newAddress(addr)
+	return g.Params.Ring.ClockwiseDistance(g.Addr.GetHash(), haddr.GetHash())
+}
+
+func (g *Geminus) CounterClockwiseDistance(addr string) *big.Int {
+	haddr := g.newAddress(addr)
+	return g.Params.Ring.CounterClockwiseDistance(g.Addr.GetHash(), haddr.GetHash())
+}
+
+func (g *Geminus) Distance(addr string) *big.Int {
+	haddr := g.newAddress(addr)
+	return g.Params.Ring.ShortestDistance(g.Addr.GetHash(), haddr.GetHash())
+}
+
+// ClosestPeers returns up to n known peers, across every club, ordered by
+// their shortest ring distance to addr.
+func (g *Geminus) ClosestPeers(addr string, n int) []Addressing.Addr {
+	haddr := g.newAddress(addr)
+
+	peers := g.GetState()
+	distances := make(map[Addressing.Addr]*big.Int, len(peers))
+	for _, v := range peers {
+		distances[v] = g.Params.Ring.ShortestDistance(v.GetHash(), haddr.GetHash())
+	}
+
+	sort.SliceStable(peers, func(i, j int) bool {
+		return distances[peers[i]].Cmp(distances[peers[j]]) < 0
+	})
+
+	if n >= 0 && n < len(peers) {
+		peers = peers[:n]
+	}
+
+	return peers
+}
+
+// Route picks the next hop towards destination with the configured
+// routing strategy, DefaultStrategy unless told otherwise.
 func (g *Geminus) Route(destination string) (Addressing.Addr, RoutingStatus) {
-	var foundAddr Addressing.Addr
-	var status RoutingStatus
-
-	// a lot of functions like this hash the given string address
-	// we should hash a given address once (from the arguments)
-	// to avoid unnecessary resource waste
-
-	// TODO: add numerical distance routing
-	if belongs, _ := g.BelongsInClub(Hat, destination); belongs {
-		foundAddr = g.SearchState(Hat, destination)
-		status = HatRoute
-	}
-
-	if foundAddr == nil {
-		haddr := Addressing.NewAddress(destination, true)
-		bootClub, _ := g.GetClub(Boot)
-		for _, baddr := range bootClub {
-			haveSameHatClub, err := g.HaveSameClub(Hat, haddr, baddr)
-			if err != nil {
-				panic(err)
-			}
-			if haveSameHatClub {
-				foundAddr = baddr
-				status = BootForward
-				break
-			}
-		}
+	return g.route(g.clubState(), g.newAddress(destination))
+}
+
+// RouteAvoiding routes as if the avoided peers, raw addresses, were in
+// none of our clubs.
+func (g *Geminus) RouteAvoiding(destination string, avoid ...string) (Addressing.Addr, RoutingStatus) {
+	avoided := make(map[string]bool, len(avoid))
+	for _, addr := range avoid {
+		avoided[string(g.newAddress(addr).GetHash())] = true
 	}
 
-	if foundAddr == nil {
-		hatClub, _ := g.GetClub(Hat)
-		hatClubSize := len(hatClub)
+	state := g.clubState().Without(func(v Addressing.Addr) bool {
+		return avoided[string(v.GetHash())]
+	})
+
+	return g.route(state, g.newAddress(destination))
+}
+
+// route asks the strategy, except for destinations among our Neighbors,
+// which strategies know nothing about. A lonely island the strategy finds
+// no way out of goes through the neighbor closest to the destination.
+func (g *Geminus) route(state *ClubState, destination Addressing.Addr) (Addressing.Addr, RoutingStatus) {
+	strategy := g.Params.Strategy
+	if strategy == nil {
+		strategy = DefaultStrategy{}
+	}
 
-		haddr := Addressing.NewAddress(destination, true)
+	state = g.healthy(state)
+	if found := state.First(Neighbors, func(v Addressing.Addr) bool { return isAddr(v, destination) }); found != nil {
+		return found, NeighborRoute
+	}
 
-		haveSameBootCase := true
-		for hatClubSize > 0 && haveSameBootCase {
-			foundAddr = hatClub[Tools.PickRandom(1, hatClubSize+1)]
-			haveSameBootCase, _ = g.HaveSameClub(Boot, foundAddr, haddr)
+	next, status := strategy.Next(state, destination)
+	if next == nil {
+		if found := state.Closest(Neighbors, destination); found != nil {
+			return found, RingForward
 		}
+	}
+
+	return next, status
+}
+
+// healthy leaves out of state the members the failure detector suspects
+// or declared dead.
+func (g *Geminus) healthy(state *ClubState) *ClubState {
+	unhealthy, _ := g.detector.unhealthy.Load().(map[string]bool)
+	if len(unhealthy) == 0 {
+		return state
+	}
+
+	return state.Without(func(v Addressing.Addr) bool {
+		return unhealthy[string(v.GetHash())]
+	})
+}
 
-		status = RandomForward
+// clubState returns the latest published clubs. The snapshot is shared
+// between readers and must never be modified.
+func (g *Geminus) clubState() *ClubState {
+	if state, ok := g.snapshot.Load().(*ClubState); ok {
+		return state
 	}
 
-	if foundAddr == nil {
-		status = Undefined
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return &ClubState{
+		Params: g.Params,
+		Self:   g.Addr,
+		Clubs:  g.Clubs,
 	}
+}
+
+// copyClubs shallow copies the club map so a writer can swap some of its
+// slices; callers hold g.mu.
+func (g *Geminus) copyClubs() map[Club][]Addressing.Addr {
+	clubs := make(map[Club][]Addressing.Addr, len(g.Clubs))
+	for k, v := range g.Clubs {
+		clubs[k] = v
+	}
+	return clubs
+}
 
-	return foundAddr, status
+// publish makes clubs the state every reader sees from now on; callers
+// hold g.mu or own g exclusively.
+func (g *Geminus) publish(clubs map[Club][]Addressing.Addr) {
+	g.Clubs = clubs
+	g.snapshot.Store(&ClubState{
+		Params: g.Params,
+		Self:   g.Addr,
+		Clubs:  clubs,
+	})
 }
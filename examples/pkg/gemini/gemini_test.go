@@ -2,7 +2,10 @@ package gemini
 
 import (
 	bytes "bytes"
+	"fmt"
+	Addressing "gemelos/pkg/addressing"
 	"testing"
+	"time"
 )
 
 func TestNewGeminus(t *testing.T) {
@@ -38,6 +41,72 @@ func TestNewGeminus(t *testing.T) {
 	}
 }
 
+func TestNewGeminusWithHasher(t *testing.T) {
+	gParams := NewGeminiConfigWithHasher(6000, Addressing.SHA256Hasher{}, 3, 3)
+	g := NewGeminus("10.10.210.21", gParams)
+
+	if err := g.Init(); err != nil {
+		t.Log("Faulty Gemini Address Length Param or Hash Function", err)
+		t.Fail()
+	}
+
+	if g.Params.AddrLength != 256 || g.Params.Ring.Order != 256 {
+		t.Log("Address length does not follow the hasher digest size")
+		t.Fail()
+	}
+
+	gParams = NewGeminiConfig(6000, 256, 3, 3)
+	g = NewGeminus("10.10.210.21", gParams)
+
+	if err := g.Init(); err == nil {
+		t.Log("A 256 bit network should not accept SHA-1 addresses")
+		t.Fail()
+	}
+}
+
+func TestNewGeminusFromIdentity(t *testing.T) {
+	identity, _ := Addressing.NewNodeIdentity(nil)
+	g := NewGeminusFromIdentity(identity, "10.10.210.21:4000", NewGeminiConfig(6000, 160, 3, 3))
+
+	if err := g.Init(); err != nil {
+		t.Log("Faulty Gemini Address Length Param or Hash Function", err)
+		t.Fail()
+	}
+
+	moved := NewGeminusFromIdentity(identity, "10.10.210.22:4000", NewGeminiConfig(6000, 160, 3, 3))
+	moved.Init()
+
+	if !bytes.Equal(g.Addr.GetHash(), moved.Addr.GetHash()) {
+		t.Log("Geminus ring position should follow its public key, not its endpoint")
+		t.Fail()
+	}
+
+	if !g.Identity.Verify([]byte("join"), identity.Sign([]byte("join"))) {
+		t.Log("Geminus identity does not verify its own signature")
+		t.Fail()
+	}
+}
+
+func TestPeersAgreeOnIds(t *testing.T) {
+	a := NewGeminus("10.10.210.21", NewGeminiConfig(6000, 160, 3, 3))
+	b := NewGeminus("10.10.210.22", NewGeminiConfig(6000, 160, 3, 3))
+
+	a.Init()
+	b.Init()
+
+	if !bytes.Equal(a.newAddress(b.Addr.GetRaw()).GetHash(), b.Addr.GetHash()) {
+		t.Log("Peer id differs from the one the peer derived for itself")
+		t.Fail()
+	}
+
+	aSeesB, _ := a.BelongsInClub(Hat, b.Addr.GetRaw())
+	bSeesA, _ := b.BelongsInClub(Hat, a.Addr.GetRaw())
+	if aSeesB != bSeesA {
+		t.Log("Peers disagree on their Hat Club membership")
+		t.Fail()
+	}
+}
+
 func TestSetState(t *testing.T) {
 	addr := "10.10.210.21"
 
@@ -60,7 +129,14 @@ func TestSetState(t *testing.T) {
 
 	for k, v := range addressMap {
 		foundAddr, status := g.Route(k)
-		if foundAddr.GetRaw() != k && status != RandomForward && status != BootForward {
+		if foundAddr == nil {
+			if v != Unrecognized || status != Undefined {
+				t.Log("Only addresses outside of every club may be left without a route")
+				t.Fail()
+			}
+			continue
+		}
+		if foundAddr.GetRaw() != k && status != RandomForward && status != BootForward && status != RingForward {
 			t.Log("Found address is not we are trying to route to")
 			t.Fail()
 		}
@@ -70,7 +146,7 @@ func TestSetState(t *testing.T) {
 		} else if v == Boot && foundAddr.GetRaw() == k && status != BootForward {
 			t.Log("Address belongs to Boot Club but RoutingStatus is not BootFind")
 			t.Fail()
-		} else if v == Unrecognized && status != RandomForward && status != BootForward {
+		} else if v == Unrecognized && status != RandomForward && status != BootForward && status != RingForward {
 			t.Log(status)
 			t.Log("Address belongs to no club but RoutingStatus is not Forward")
 			t.Fail()
@@ -99,9 +175,280 @@ func TestIsInBootClub(t *testing.T) {
 }
 
 func TestGetAddrDistance(t *testing.T) {
-	t.Log("No test case for Gemini.GetAddrDistance")
+	gParams := NewGeminiConfig(6000, 160, 3, 3)
+	g := NewGeminus("10.10.210.21", gParams)
+
+	g.Init()
+
+	if g.GetAddrDistance(g.Addr.GetRaw()) != 0 {
+		t.Log("Distance to self is not zero")
+		t.Fail()
+	}
+
+	addr := "41.210.412.312"
+	if g.GetAddrDistance(addr) != g.Distance(addr).BitLen() {
+		t.Log("Faulty logarithmic distance")
+		t.Fail()
+	}
+
+	if g.Distance(addr).Cmp(g.ClockwiseDistance(addr)) > 0 || g.Distance(addr).Cmp(g.CounterClockwiseDistance(addr)) > 0 {
+		t.Log("Shortest distance is longer than one of the directions")
+		t.Fail()
+	}
+}
+
+func TestClosestPeers(t *testing.T) {
+	gParams := NewGeminiConfig(6000, 160, 3, 3)
+	g := NewGeminus("10.10.210.21", gParams)
+
+	g.Init()
+
+	for i := 0; len(g.GetState()) < 10; i++ {
+		g.SetState(fmt.Sprintf("10.30.%d.%d", i/256, i%256))
+	}
+
+	target := "41.210.412.312"
+	closest := g.ClosestPeers(target, 3)
+
+	if len(closest) != 3 {
+		t.Log("Faulty number of closest peers", len(closest))
+		t.Fail()
+	}
+
+	htarget := Addressing.NewAddress(target, true)
+	for i := 1; i < len(closest); i++ {
+		prev := gParams.Ring.ShortestDistance(closest[i-1].GetHash(), htarget.GetHash())
+		next := gParams.Ring.ShortestDistance(closest[i].GetHash(), htarget.GetHash())
+		if prev.Cmp(next) > 0 {
+			t.Log("Closest peers are not ordered by distance")
+			t.Fail()
+		}
+	}
+
+	if len(g.ClosestPeers(target, 100)) != len(g.GetState()) {
+		t.Log("Closest peers should be capped by the known peers")
+		t.Fail()
+	}
 }
 
 func TestRoute(t *testing.T) {
-	t.Log("No test case for Gemini.Route")
+	gParams := NewGeminiConfig(6000, 160, 3, 3)
+	g := NewGeminus("10.10.210.21", gParams)
+
+	g.Init()
+
+	hatAddresses := make([]string, 0, 6)
+	for i := 0; len(hatAddresses) < cap(hatAddresses); i++ {
+		addr := fmt.Sprintf("10.20.%d.%d", i/256, i%256)
+		if belongs, _ := g.BelongsInClub(Hat, addr); belongs {
+			hatAddresses = append(hatAddresses, addr)
+		}
+	}
+
+	known, unknown := hatAddresses[:5], hatAddresses[5]
+	for _, addr := range known {
+		g.SetState(addr)
+	}
+
+	foundAddr, status := g.Route(known[0])
+	if status != HatRoute || foundAddr.GetRaw() != known[0] {
+		t.Log("Known Hat Club member was not routed to directly")
+		t.Fail()
+	}
+
+	foundAddr, status = g.Route(unknown)
+	if status != HatClosestForward {
+		t.Log("Unknown Hat Club address was not forwarded to the closest member", status)
+		t.Fail()
+	}
+
+	hunknown := Addressing.NewAddress(unknown, true)
+	for _, v := range g.Clubs[Hat] {
+		if gParams.Ring.Closer(v.GetHash(), foundAddr.GetHash(), hunknown.GetHash()) {
+			t.Log("Forwarded address is not the numerically closest Hat Club member")
+			t.Fail()
+		}
+	}
+}
+
+// hatPeers returns the first n addresses that only belong in the Hat club
+// of g.
+func hatPeers(g *Geminus, n int) []string {
+	peers := make([]string, 0, n)
+	for i := 0; len(peers) < n; i++ {
+		addr := fmt.Sprintf("10.30.%d.%d", i/256, i%256)
+		inHat, _ := g.BelongsInClub(Hat, addr)
+		inBoot, _ := g.BelongsInClub(Boot, addr)
+		if inHat && !inBoot {
+			peers = append(peers, addr)
+		}
+	}
+	return peers
+}
+
+func TestAddInClubDeduplicates(t *testing.T) {
+	g := NewGeminus("10.10.210.21", NewGeminiConfig(6000, 160, 3, 3))
+	g.Init()
+
+	addr := hatPeers(g, 1)[0]
+	g.SetState(addr)
+	g.SetState(addr)
+	g.AddInClub(Hat, Addressing.NewAddress(addr, true))
+
+	if hat, _ := g.GetClub(Hat); len(hat) != 1 {
+		t.Log("A peer should be kept once however often it is added", len(hat))
+		t.Fail()
+	}
+}
+
+func TestAddAllInClub(t *testing.T) {
+	gParams := NewGeminiConfig(6000, 160, 3, 3)
+	gParams.ClubSize[Hat] = 4
+	g := NewGeminus("10.10.210.21", gParams)
+	g.Init()
+
+	peers := hatPeers(g, 7)
+	g.SetState(peers[0])
+	g.tombstones.bury(g.newAddress(peers[6]), time.Now().Add(time.Minute))
+
+	members := make([]Addressing.Addr, 0, len(peers)+1)
+	for _, addr := range peers {
+		members = append(members, g.newAddress(addr))
+	}
+	members = append(members, g.newAddress(peers[1]))
+
+	err := g.AddAllInClub(Hat, members)
+	failed, ok := err.(*AddError)
+	if !ok || failed.Club != Hat || len(failed.Peers) != len(failed.Errs) {
+		t.Fatal("Peers left out should be returned as an AddError", err)
+	}
+
+	hat, _ := g.GetClub(Hat)
+	if len(hat) != 4 {
+		t.Log("Adding peers at once should keep them once and within the club bound", len(hat))
+		t.Fail()
+	}
+
+	for i, v := range failed.Peers {
+		if indexOf(hat, v) >= 0 {
+			t.Log("Peers returned should not be in the club", v.GetRaw())
+			t.Fail()
+		}
+		if isAddr(v, members[6]) && failed.Errs[i] != ErrPeerLeft {
+			t.Log("A peer that left should be returned with ErrPeerLeft", failed.Errs[i])
+			t.Fail()
+		}
+	}
+
+	if len(g.GetState()) != len(g.peers) {
+		t.Log("Only the peers kept should be known", len(g.GetState()), len(g.peers))
+		t.Fail()
+	}
+
+	again := []Addressing.Addr{g.newAddress(hat[2].GetRaw()), g.newAddress(hat[0].GetRaw())}
+	if err := g.AddAllInClub(Hat, again); err != nil {
+		t.Log("Refreshing members should not fail", err)
+		t.Fail()
+	}
+	if refreshed, _ := g.GetClub(Hat); len(refreshed) != 4 || refreshed[0] != again[1] || refreshed[2] != again[0] {
+		t.Log("Members added again should be replaced in place by the new addresses")
+		t.Fail()
+	}
+
+	if err := g.AddAllInClub(Unrecognized, members); err == nil {
+		t.Log("Adding peers to an unknown club should fail")
+		t.Fail()
+	}
+}
+
+func TestRemoveFromClub(t *testing.T) {
+	g := NewGeminus("10.10.210.21", NewGeminiConfig(6000, 160, 3, 3))
+	g.Init()
+
+	peers := hatPeers(g, 3)
+	for _, addr := range peers {
+		g.SetState(addr)
+	}
+
+	if err := g.RemoveFromClub(Hat, Addressing.NewAddress(peers[1], true)); err != nil {
+		t.Log("Hat Club member could not be removed", err)
+		t.Fail()
+	}
+
+	if g.SearchState(Hat, peers[1]) != nil || g.SearchState(Hat, peers[0]) == nil || g.SearchState(Hat, peers[2]) == nil {
+		t.Log("RemoveFromClub should only drop the given peer")
+		t.Fail()
+	}
+
+	if _, known := g.Peer(peers[1]); known {
+		t.Log("A peer in no club anymore should be forgotten")
+		t.Fail()
+	}
+
+	if err := g.RemoveFromClub(Hat, Addressing.NewAddress(peers[1], true)); err == nil {
+		t.Log("Removing a peer twice should fail")
+		t.Fail()
+	}
+
+	if err := g.Evict(peers[0]); err != nil || g.SearchState(Hat, peers[0]) != nil {
+		t.Log("Evict should drop the peer from every club", err)
+		t.Fail()
+	}
+
+	if err := g.Evict(peers[0]); err == nil {
+		t.Log("Evicting an unknown peer should fail")
+		t.Fail()
+	}
+}
+
+func TestBoundedClubSize(t *testing.T) {
+	gParams := NewGeminiConfig(6000, 160, 3, 3)
+	gParams.ClubSize[Hat] = 4
+	gParams.Eviction = LowestScore{}
+
+	g := NewGeminus("10.10.210.21", gParams)
+	g.Init()
+
+	peers := hatPeers(g, 6)
+	for i, addr := range peers[:4] {
+		g.SetState(addr)
+		g.SetScore(addr, float64(i))
+	}
+
+	g.SetState(peers[4])
+
+	hat, _ := g.GetClub(Hat)
+	if len(hat) != 4 {
+		t.Log("Hat Club should not grow past its size", len(hat))
+		t.Fail()
+	}
+
+	if g.SearchState(Hat, peers[4]) == nil || g.SearchState(Hat, peers[0]) != nil {
+		t.Log("The lowest scored member should have made room for the newcomer")
+		t.Fail()
+	}
+
+	g.SetScore(peers[4], 5)
+	g.Params.Eviction = FurthestPeer{}
+
+	g.SetState(peers[5])
+
+	hat, _ = g.GetClub(Hat)
+	if len(hat) != 4 {
+		t.Log("Hat Club should not grow past its size", len(hat))
+		t.Fail()
+	}
+
+	furthest := g.Params.Ring.ShortestDistance(g.Addr.GetHash(), hat[0].GetHash())
+	for _, v := range hat {
+		if d := g.Params.Ring.ShortestDistance(g.Addr.GetHash(), v.GetHash()); d.Cmp(furthest) > 0 {
+			furthest = d
+		}
+	}
+	for _, addr := range peers[1:] {
+		if g.SearchState(Hat, addr) == nil && g.Distance(addr).Cmp(furthest) < 0 {
+			t.Log("A peer closer than the kept members was evicted", addr)
+			t.Fail()
+		}
+	}
 }
@@ -0,0 +1,181 @@
+package gemini
+
+import (
+	"context"
+	Addressing "gemelos/pkg/addressing"
+	"math/big"
+	"sort"
+)
+
+// Neighbors is the fallback club of a lonely island, a node alone in one
+// of its cases: the peers closest to it on the ring on either side,
+// whatever their cases. The peers it picks keep it in their own Neighbors
+// club in return, which is how routing still finds it.
+const Neighbors Club = "Neighbors"
+
+const (
+	NeighborRoute RoutingStatus = "NeighborRoute"
+	RingForward                 = "RingForward"
+)
+
+// DefaultFallbackNeighbors is how many neighbors a lonely island adopts,
+// half of them on each side of it.
+const DefaultFallbackNeighbors = 4
+
+// HasClub reports whether a node keeps the given club: one per case, and
+// Neighbors.
+func (gc *GeminiConfig) HasClub(club Club) bool {
+	_, ok := gc.Case(club)
+	return ok || club == Neighbors
+}
+
+// clubNames lists every club a node keeps, in case definition order and
+// Neighbors last.
+func (gc *GeminiConfig) clubNames() []Club {
+	names := make([]Club, 0, len(gc.Cases)+1)
+	for _, cd := range gc.Cases {
+		names = append(names, cd.Name)
+	}
+	return append(names, Neighbors)
+}
+
+// Isolated returns the cases the node is alone in as far as it knows,
+// counting only the members the failure detector deems alive.
+func (g *Geminus) Isolated() []Club {
+	clubs := g.healthy(g.clubState()).Clubs
+
+	isolated := make([]Club, 0)
+	for _, cd := range g.Params.Cases {
+		if len(clubs[cd.Name]) == 0 {
+			isolated = append(isolated, cd.Name)
+		}
+	}
+	return isolated
+}
+
+// Repair adopts fallback neighbors when the node is isolated in one of its
+// cases and short of healthy neighbors, which is otherwise a no-op. Join
+// and Monitor call it, so a node repairs itself as soon as it notices it
+// is a lonely island.
+func (g *Geminus) Repair(ctx context.Context) error {
+	return g.repair(ctx, nil)
+}
+
+// repair looks the ring neighbors of the node up iteratively, starting from
+// the known peers and hints: the closest candidates on either side are
+// asked to take us as their neighbor and for the peers they know close to
+// us, until no closer one turns up.
+func (g *Geminus) repair(ctx context.Context, hints []string) error {
+	if len(g.Isolated()) == 0 || len(g.healthy(g.clubState()).Clubs[Neighbors]) >= g.fallbackNeighbors() {
+		return nil
+	}
+	if g.Transport == nil {
+		return ErrNoTransport
+	}
+
+	candidates := g.GetState()
+	for _, addr := range hints {
+		candidates = append(candidates, g.newAddress(addr))
+	}
+
+	asked := map[string]bool{string(g.Addr.GetHash()): true}
+	answered := make([]Addressing.Addr, 0)
+
+	for round := uint32(0); round < g.ttl(); round++ {
+		progressed := false
+
+		for _, v := range g.ringNeighbors(g.Addr, candidates, g.fallbackNeighbors()) {
+			if asked[string(v.GetHash())] {
+				continue
+			}
+			asked[string(v.GetHash())] = true
+			progressed = true
+
+			sender, peers, err := g.askJoin(ctx, v.GetRaw(), Neighbors)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err != nil {
+				continue
+			}
+
+			answered = append(answered, g.newAddress(sender))
+			for _, addr := range peers {
+				g.SetState(addr)
+				candidates = append(candidates, g.newAddress(addr))
+			}
+		}
+
+		if !progressed {
+			break
+		}
+
+		// only the candidates that answered are worth keeping around
+		alive := make([]Addressing.Addr, 0, len(candidates))
+		for _, v := range candidates {
+			if !asked[string(v.GetHash())] || indexOf(answered, v) >= 0 {
+				alive = append(alive, v)
+			}
+		}
+		candidates = alive
+	}
+
+	for _, v := range g.ringNeighbors(g.Addr, answered, g.fallbackNeighbors()) {
+		g.AddInClub(Neighbors, v)
+	}
+
+	return nil
+}
+
+// ringNeighbors returns the n candidates closest to center, half clockwise
+// and half counter-clockwise, so both sides of the gap it sits in are
+// covered.
+func (g *Geminus) ringNeighbors(center Addressing.Addr, candidates []Addressing.Addr, n int) []Addressing.Addr {
+	unique := make([]Addressing.Addr, 0, len(candidates))
+	seen := map[string]bool{string(center.GetHash()): true}
+	for _, v := range candidates {
+		if !seen[string(v.GetHash())] && !g.tombstones.buried(v) {
+			seen[string(v.GetHash())] = true
+			unique = append(unique, v)
+		}
+	}
+
+	ring := g.Params.Ring
+	self := center.GetHash()
+
+	clockwise := make(map[string]*big.Int, len(unique))
+	counterClockwise := make(map[string]*big.Int, len(unique))
+	for _, v := range unique {
+		clockwise[string(v.GetHash())] = ring.ClockwiseDistance(self, v.GetHash())
+		counterClockwise[string(v.GetHash())] = ring.CounterClockwiseDistance(self, v.GetHash())
+	}
+
+	sort.SliceStable(unique, func(i, j int) bool {
+		return clockwise[string(unique[i].GetHash())].Cmp(clockwise[string(unique[j].GetHash())]) < 0
+	})
+	half := (n + 1) / 2
+	if half > len(unique) {
+		half = len(unique)
+	}
+	neighbors := append(make([]Addressing.Addr, 0, n), unique[:half]...)
+
+	rest := unique[half:]
+	sort.SliceStable(rest, func(i, j int) bool {
+		return counterClockwise[string(rest[i].GetHash())].Cmp(counterClockwise[string(rest[j].GetHash())]) < 0
+	})
+	for _, v := range rest {
+		if len(neighbors) == n {
+			break
+		}
+		neighbors = append(neighbors, v)
+	}
+
+	return neighbors
+}
+
+func (g *Geminus) fallbackNeighbors() int {
+	if g.Params.FallbackNeighbors > 0 {
+		return g.Params.FallbackNeighbors
+	}
+	return DefaultFallbackNeighbors
+}
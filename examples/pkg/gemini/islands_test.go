@@ -0,0 +1,92 @@
+package gemini
+
+import (
+	"context"
+	"fmt"
+	Peer "gemelos/pkg/peer"
+	"testing"
+	"time"
+)
+
+func TestLonelyIsland(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	network := Peer.NewMemoryNetwork()
+	newNode := func(addr string) *Geminus {
+		transport := network.NewTransport()
+		transport.Listen(addr)
+
+		g := NewGeminus(addr, NewGeminiConfig(200, 160, 3, 3))
+		g.Init()
+		g.Transport = transport
+		go g.Serve(ctx)
+		return g
+	}
+
+	lonely := newNode("10.81.0.1")
+	hat, _ := lonely.Params.Case(Hat)
+	boot, _ := lonely.Params.Case(Boot)
+
+	// nobody else shares the Hat or the Boot case of the lonely node
+	nodes := make([]*Geminus, 0, 200)
+	for i := 0; len(nodes) < cap(nodes); i++ {
+		addr := fmt.Sprintf("10.80.%d.%d", i/256, i%256)
+		haddr := lonely.newAddress(addr)
+		if hat.Of(haddr) != hat.Of(lonely.Addr) && boot.Of(haddr) != boot.Of(lonely.Addr) {
+			nodes = append(nodes, newNode(addr))
+		}
+	}
+	for _, g := range nodes {
+		for _, other := range nodes {
+			if g != other {
+				g.SetState(other.Addr.GetRaw())
+			}
+		}
+	}
+
+	if err := lonely.Join(ctx, nodes[0].Addr.GetRaw()); err != nil {
+		t.Fatal("Faulty join", err)
+	}
+
+	if isolated := lonely.Isolated(); len(isolated) != 2 {
+		t.Log("The node should know it is alone in both its cases", isolated)
+		t.Fail()
+	}
+
+	// the closest node on either side has to know about the lonely one
+	var clockwise, counterClockwise *Geminus
+	ring := lonely.Params.Ring
+	for _, g := range nodes {
+		if clockwise == nil || ring.ClockwiseDistance(lonely.Addr.GetHash(), g.Addr.GetHash()).Cmp(ring.ClockwiseDistance(lonely.Addr.GetHash(), clockwise.Addr.GetHash())) < 0 {
+			clockwise = g
+		}
+		if counterClockwise == nil || ring.CounterClockwiseDistance(lonely.Addr.GetHash(), g.Addr.GetHash()).Cmp(ring.CounterClockwiseDistance(lonely.Addr.GetHash(), counterClockwise.Addr.GetHash())) < 0 {
+			counterClockwise = g
+		}
+	}
+
+	for _, g := range []*Geminus{clockwise, counterClockwise} {
+		if lonely.SearchState(Neighbors, g.Addr.GetRaw()) == nil || g.SearchState(Neighbors, lonely.Addr.GetRaw()) == nil {
+			t.Log("The lonely node and its ring neighbors should adopt each other", g.Addr.GetRaw())
+			t.Fail()
+		}
+	}
+
+	for _, g := range nodes[:20] {
+		if _, err := lonely.Deliver(ctx, g.Addr.GetRaw(), nil); err != nil {
+			t.Log("Routing from a lonely island should succeed", err)
+			t.Fail()
+		}
+		if _, err := g.Deliver(ctx, lonely.Addr.GetRaw(), nil); err != nil {
+			t.Log("Routing to a lonely island should succeed", err)
+			t.Fail()
+		}
+	}
+
+	neighbors := len(nodes[0].Clubs[Neighbors])
+	if err := nodes[0].Repair(ctx); err != nil || len(nodes[0].Clubs[Neighbors]) != neighbors {
+		t.Log("Repairing a node that is not isolated should change nothing", err)
+		t.Fail()
+	}
+}
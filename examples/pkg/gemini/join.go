@@ -0,0 +1,203 @@
+package gemini
+
+import (
+	"context"
+	"errors"
+	Addressing "gemelos/pkg/addressing"
+	Peer "gemelos/pkg/peer"
+	"sync"
+)
+
+// joinHints is how many peers close to a joining node an answer adds on
+// top of the ones the joiner keeps, to lead it towards its clubs, half on
+// each side of it on the ring.
+const joinHints = 4
+
+// newcomerMemory is how many of the latest joiners a node remembers, in or
+// out of its clubs.
+const newcomerMemory = 256
+
+var ErrNoSeedAnswered = errors.New("None of the seeds answered")
+
+// newcomers are the latest nodes that joined through us. A seed does not
+// keep most of them in its clubs, yet it has to point later joiners at
+// them or the first nodes of a network would never meet.
+type newcomers struct {
+	mu    sync.Mutex
+	addrs []Addressing.Addr
+}
+
+// Join bootstraps the clubs of the node from seeds, raw addresses such as
+// the ones pkg/seed resolves. Every node asked adds us to its clubs when we
+// fit them, and answers with the peers it knows that fit ours, plus a few
+// close to us. We then ask every peer landing in our clubs the same, and
+// follow hints while a club is still empty, so clubs fill up from what the
+// peers we meet know, never from a global view of the network. A node still
+// alone in one of its cases is repaired afterwards.
+func (g *Geminus) Join(ctx context.Context, seeds ...string) error {
+	asked := map[string]bool{string(g.Addr.GetHash()): true}
+	queue := make([]string, 0)
+	heard := make([]string, 0)
+	answered := false
+
+	for _, s := range seeds {
+		seed, peers, err := g.askJoin(ctx, s, "")
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			continue
+		}
+
+		// the answer of a seed is how we learn its raw address
+		asked[string(g.newAddress(seed).GetHash())] = true
+		g.SetState(seed)
+		heard = append(heard, seed)
+
+		answered = true
+		queue = append(queue, peers...)
+	}
+
+	if !answered {
+		return ErrNoSeedAnswered
+	}
+
+	hints := uint32(0)
+	for len(queue) > 0 {
+		addr := queue[0]
+		queue = queue[1:]
+		heard = append(heard, addr)
+
+		haddr := g.newAddress(addr)
+		if asked[string(haddr.GetHash())] {
+			continue
+		}
+
+		if _, err := g.SetState(addr); err != nil {
+			if !g.hasEmptyClub() || hints >= g.ttl() {
+				continue
+			}
+			hints++
+		}
+		asked[string(haddr.GetHash())] = true
+
+		_, peers, err := g.askJoin(ctx, addr, "")
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			g.Evict(addr)
+			continue
+		}
+		queue = append(queue, peers...)
+	}
+
+	// a lonely island gets neighbors out of everyone it heard of
+	return g.repair(ctx, heard)
+}
+
+// askJoin returns who answered and the peers it told us about. Naming a
+// club asks the peer to keep us in it whether we fit it or not, which only
+// Neighbors allows.
+func (g *Geminus) askJoin(ctx context.Context, addr string, club Club) (string, []string, error) {
+	request := Peer.NewMessage(Peer.Join, g.Addr.GetRaw())
+	request.Club = string(club)
+
+	response, err := g.request(ctx, addr, request)
+	if err != nil {
+		return "", nil, err
+	}
+	if response.Type != Peer.ClubStateResponse {
+		return "", nil, ErrNoAnswer
+	}
+
+	return response.Sender, response.Peers, nil
+}
+
+// answerJoin adds the joiner to our clubs and tells it about the peers it
+// should keep in its own.
+func (g *Geminus) answerJoin(request *Peer.Message) {
+	joiner := g.newAddress(request.Sender)
+	g.revive(request.Sender)
+	g.SetState(request.Sender)
+	if Club(request.Club) == Neighbors {
+		g.AddInClub(Neighbors, joiner)
+	}
+	known := g.newcomers.remember(joiner)
+
+	response := Peer.NewMessage(Peer.ClubStateResponse, g.Addr.GetRaw())
+	response.Nonce = request.Nonce
+
+	added := map[string]bool{string(joiner.GetHash()): true}
+	add := func(v Addressing.Addr) {
+		if v != nil && !added[string(v.GetHash())] {
+			added[string(v.GetHash())] = true
+			response.Peers = append(response.Peers, v.GetRaw())
+		}
+	}
+
+	for _, v := range append(g.GetState(), known...) {
+		for _, cd := range g.Params.Cases {
+			if cd.Of(joiner) == cd.PeerCase(v) {
+				add(v)
+				break
+			}
+		}
+	}
+
+	next, _ := g.Route(request.Sender)
+	add(next)
+	for _, v := range g.ringNeighbors(joiner, g.GetState(), joinHints) {
+		add(v)
+	}
+
+	g.sign(response)
+	g.Transport.Send(Addressing.Endpoint(request.Sender), Peer.Encode(response))
+}
+
+// answerClubState hands out the members of one of our clubs, or every
+// known peer when no club is named.
+func (g *Geminus) answerClubState(request *Peer.Message) {
+	response := Peer.NewMessage(Peer.ClubStateResponse, g.Addr.GetRaw())
+	response.Nonce = request.Nonce
+	response.Club = request.Club
+
+	members := g.GetState()
+	if request.Club != "" {
+		members, _ = g.GetClub(Club(request.Club))
+	}
+	for _, v := range members {
+		response.Peers = append(response.Peers, v.GetRaw())
+	}
+
+	g.sign(response)
+	g.Transport.Send(Addressing.Endpoint(request.Sender), Peer.Encode(response))
+}
+
+// remember adds v to the newcomers and returns the ones known before it.
+func (n *newcomers) remember(v Addressing.Addr) []Addressing.Addr {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	known := append([]Addressing.Addr(nil), n.addrs...)
+
+	if i := indexOf(n.addrs, v); i >= 0 {
+		n.addrs = append(n.addrs[:i:i], n.addrs[i+1:]...)
+	}
+	if len(n.addrs) >= newcomerMemory {
+		n.addrs = n.addrs[1:]
+	}
+	n.addrs = append(n.addrs, v)
+
+	return known
+}
+
+func (g *Geminus) hasEmptyClub() bool {
+	clubs := g.clubState().Clubs
+	for _, cd := range g.Params.Cases {
+		if len(clubs[cd.Name]) == 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package gemini
+
+import (
+	"context"
+	"fmt"
+	Peer "gemelos/pkg/peer"
+	"testing"
+	"time"
+)
+
+func TestJoin(t *testing.T) {
+	const count = 300
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	network := Peer.NewMemoryNetwork()
+	nodes := make([]*Geminus, count)
+
+	for i := range nodes {
+		addr := fmt.Sprintf("10.70.%d.%d", i/256, i%256)
+		transport := network.NewTransport()
+		transport.Listen(addr)
+
+		nodes[i] = NewGeminus(addr, NewGeminiConfig(count, 160, 3, 3))
+		nodes[i].Init()
+		nodes[i].Transport = transport
+		go nodes[i].Serve(ctx)
+
+		if i == 0 {
+			continue
+		}
+
+		if err := nodes[i].Join(ctx, nodes[0].Addr.GetRaw(), "10.70.255.255"); err != nil {
+			t.Fatal("Faulty join", err)
+		}
+	}
+
+	// every node should know the nodes it shares a case with
+	missing, expected := 0, 0
+	for _, g := range nodes {
+		for _, other := range nodes {
+			if g == other {
+				continue
+			}
+			for _, cd := range g.Params.Cases {
+				if cd.Of(g.Addr) == cd.PeerCase(other.Addr) {
+					expected++
+					if g.SearchState(cd.Name, other.Addr.GetRaw()) == nil {
+						missing++
+					}
+				}
+			}
+		}
+	}
+
+	if missing > expected/100 {
+		t.Log("Clubs are missing members after joining", missing, "out of", expected)
+		t.Fail()
+	}
+
+	failures := 0
+	for i := 0; i < 50; i++ {
+		if _, err := nodes[i].Deliver(ctx, nodes[count-1-i].Addr.GetRaw(), nil); err != nil {
+			failures++
+		}
+	}
+
+	if failures > 2 {
+		t.Log("Joined network does not route", failures)
+		t.Fail()
+	}
+
+	lonely := NewGeminus("10.70.254.1", NewGeminiConfig(count, 160, 3, 3))
+	lonely.Init()
+	transport := network.NewTransport()
+	transport.Listen("10.70.254.1")
+	lonely.Transport = transport
+	lonely.Params.RequestTimeout = 50 * time.Millisecond
+
+	if err := lonely.Join(ctx, "10.70.255.254"); err != ErrNoSeedAnswered {
+		t.Log("Joining without any seed answering should fail", err)
+		t.Fail()
+	}
+}
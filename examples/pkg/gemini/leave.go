@@ -0,0 +1,101 @@
+package gemini
+
+import (
+	"context"
+	"errors"
+	Addressing "gemelos/pkg/addressing"
+	Peer "gemelos/pkg/peer"
+	"sync"
+	"time"
+)
+
+// DefaultTombstoneTTL is how long a node refuses to hear about a peer that
+// left, unless the peer itself comes back.
+const DefaultTombstoneTTL = 10 * time.Minute
+
+var ErrPeerLeft = errors.New("Peer has left the network")
+
+// tombstones are the peers that left, until when they are considered gone.
+// Gossip naming them is ignored meanwhile, so a peer that left does not
+// come back through the stale clubs of someone else.
+type tombstones struct {
+	mu    sync.Mutex
+	until map[string]time.Time
+}
+
+// Leave tells every known peer we are going away, so they drop us at once
+// instead of finding out through failed hops.
+func (g *Geminus) Leave(ctx context.Context) error {
+	if g.Transport == nil {
+		return ErrNoTransport
+	}
+
+	m := Peer.NewMessage(Peer.Leave, g.Addr.GetRaw())
+	g.sign(m)
+	payload := Peer.Encode(m)
+
+	for _, v := range g.GetState() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		g.Transport.Send(Addressing.Endpoint(v.GetRaw()), payload)
+	}
+
+	return nil
+}
+
+// answerLeave drops the sender from every club. Unsigned leaves are only
+// taken from the endpoint they name, signed ones were checked already.
+func (g *Geminus) answerLeave(envelope Peer.Envelope, m *Peer.Message) {
+	if _, signed := Addressing.PublicKeyOf(m.Sender); !signed && Addressing.Endpoint(m.Sender) != envelope.From {
+		return
+	}
+
+	haddr := g.newAddress(m.Sender)
+	g.tombstones.bury(haddr, time.Now().Add(g.tombstoneTTL()))
+	g.Evict(m.Sender)
+}
+
+// revive lifts the tombstone of a peer that talks to us again itself, and
+// takes it for alive if we had declared it dead.
+func (g *Geminus) revive(addr string) {
+	haddr := g.newAddress(addr)
+	g.tombstones.revive(haddr)
+	g.setLiveness(haddr, Alive)
+}
+
+func (g *Geminus) tombstoneTTL() time.Duration {
+	if g.Params.TombstoneTTL > 0 {
+		return g.Params.TombstoneTTL
+	}
+	return DefaultTombstoneTTL
+}
+
+func (t *tombstones) bury(v Addressing.Addr, until time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.until == nil {
+		t.until = make(map[string]time.Time)
+	}
+	t.until[string(v.GetHash())] = until
+}
+
+func (t *tombstones) revive(v Addressing.Addr) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.until, string(v.GetHash()))
+}
+
+func (t *tombstones) buried(v Addressing.Addr) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	until, ok := t.until[string(v.GetHash())]
+	if ok && time.Now().After(until) {
+		delete(t.until, string(v.GetHash()))
+		return false
+	}
+	return ok
+}
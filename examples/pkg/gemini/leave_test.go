@@ -0,0 +1,107 @@
+package gemini
+
+import (
+	"context"
+	Peer "gemelos/pkg/peer"
+	"testing"
+	"time"
+)
+
+// knownBy returns how many nodes keep g in one of their clubs.
+func knownBy(nodes []*Geminus, g *Geminus) int {
+	count := 0
+	for _, other := range nodes {
+		for _, cd := range other.Params.Cases {
+			if other.SearchState(cd.Name, g.Addr.GetRaw()) != nil {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
+
+func TestLeave(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	nodes := newMemoryNodes(ctx, 200, func() *GeminiConfig { return NewGeminiConfig(200, 160, 3, 3) })
+	leaving := nodes[42]
+
+	if knownBy(nodes, leaving) == 0 {
+		t.Fatal("The leaving node should start out in some clubs")
+	}
+
+	if err := leaving.Leave(ctx); err != nil {
+		t.Fatal("Faulty leave", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for knownBy(nodes, leaving) > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if knownBy(nodes, leaving) > 0 {
+		t.Log("Club members should drop a peer as soon as it leaves")
+		t.Fail()
+	}
+
+	var member *Geminus
+	for _, g := range nodes {
+		if inHat, _ := g.BelongsInClub(Hat, leaving.Addr.GetRaw()); inHat && g != leaving {
+			member = g
+			break
+		}
+	}
+
+	if _, err := member.SetState(leaving.Addr.GetRaw()); err != ErrPeerLeft {
+		t.Log("Stale gossip should not bring back a peer that left", err)
+		t.Fail()
+	}
+
+	if err := leaving.Join(ctx, member.Addr.GetRaw()); err != nil {
+		t.Fatal("Faulty rejoin", err)
+	}
+
+	if member.SearchState(Hat, leaving.Addr.GetRaw()) == nil {
+		t.Log("A peer coming back by itself should be let back in")
+		t.Fail()
+	}
+}
+
+func TestForwardFallsBackOnGoneHop(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	network := Peer.NewMemoryNetwork()
+	newNode := func(addr string) *Geminus {
+		transport := network.NewTransport()
+		transport.Listen(addr)
+
+		g := NewGeminus(addr, NewGeminiConfig(6000, 160, 3, 3))
+		g.Init()
+		g.Transport = transport
+		go g.Serve(ctx)
+		return g
+	}
+
+	a, gone, c, d := newNode("10.90.2.1"), newNode("10.90.2.2"), newNode("10.90.2.3"), newNode("10.90.2.4")
+
+	a.AddInClub(Hat, gone.Addr)
+	a.AddInClub(Hat, c.Addr)
+	c.AddInClub(Hat, d.Addr)
+	a.Params.Strategy = preferStrategy{gone.Addr, c.Addr}
+	c.Params.Strategy = preferStrategy{}
+
+	gone.Transport.Close()
+
+	if _, err := a.Deliver(ctx, d.Addr.GetRaw(), nil); err != nil {
+		t.Log("Delivery should fall back to another club member", err)
+		t.Fail()
+	}
+
+	if a.SearchState(Hat, gone.Addr.GetRaw()) != nil {
+		t.Log("A next hop known to be gone should be dropped")
+		t.Fail()
+	}
+}
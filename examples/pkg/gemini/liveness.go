@@ -0,0 +1,358 @@
+package gemini
+
+import (
+	"context"
+	"errors"
+	Addressing "gemelos/pkg/addressing"
+	Peer "gemelos/pkg/peer"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Liveness string
+
+const (
+	Alive   Liveness = "Alive"
+	Suspect          = "Suspect"
+	Dead             = "Dead"
+)
+
+const (
+	DefaultProbeInterval    = time.Second
+	DefaultSuspicionTimeout = 5 * time.Second
+	DefaultIndirectProbes   = 3
+	DefaultIndirectRelays   = 16
+)
+
+var ErrPeerDead = errors.New("Peer was declared dead")
+
+// detector is the failure detector state: the members left to probe this
+// round, the indirect probes being relayed for others, and the set routing
+// skips, published like the clubs so Route reads it without locking.
+type detector struct {
+	mu        sync.Mutex
+	order     []Addressing.Addr
+	relays    int
+	unhealthy atomic.Value
+}
+
+// Monitor runs the failure detector, one Probe every ProbeInterval, until
+// ctx is cancelled, and repairs the node whenever losing members turned it
+// into a lonely island. Serve has to be running for the probes to be
+// answered.
+func (g *Geminus) Monitor(ctx context.Context) error {
+	if g.Transport == nil {
+		return ErrNoTransport
+	}
+
+	ticker := time.NewTicker(g.probeInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			g.Probe(ctx)
+			g.Repair(ctx)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Probe is one SWIM protocol period. The next club member in a shuffled
+// round robin is pinged; if it does not answer, IndirectProbes other
+// members are asked to ping it for us, so a lossy link between two nodes
+// alone does not get a peer suspected. A member nobody reached turns
+// Suspect, and Dead once it stayed suspected for SuspicionTimeout, at which
+// point it is evicted from every club: holding on to it would only keep it
+// out of routing while taking the room of a live peer. Any answer before
+// that makes it Alive again.
+//
+// A dead peer is remembered for TombstoneTTL, and refused by AddInClub,
+// AddAllInClub and SetState meanwhile, so stale gossip does not bring it
+// back. Answering a ping or joining again lifts that.
+//
+// Suspicion is local: it is not gossiped, every node makes up its own mind
+// about its own club members.
+func (g *Geminus) Probe(ctx context.Context) error {
+	if g.Transport == nil {
+		return ErrNoTransport
+	}
+
+	g.expireSuspects()
+
+	target := g.nextProbe()
+	if target == nil {
+		return nil
+	}
+
+	if g.ping(ctx, target) || g.pingIndirect(ctx, target) {
+		g.setLiveness(target, Alive)
+	} else if ctx.Err() == nil {
+		g.setLiveness(target, Suspect)
+	}
+
+	return ctx.Err()
+}
+
+// Liveness tells what the failure detector makes of a club member, or of
+// a peer it declared dead.
+func (g *Geminus) Liveness(addr string) (Liveness, bool) {
+	info, ok := g.Peer(addr)
+	if !ok {
+		return "", false
+	}
+	return info.Liveness, true
+}
+
+// nextProbe pops the next member to probe, reshuffling the members once
+// every one of them was probed.
+func (g *Geminus) nextProbe() Addressing.Addr {
+	g.detector.mu.Lock()
+	defer g.detector.mu.Unlock()
+
+	for {
+		if len(g.detector.order) == 0 {
+			members := g.GetState()
+			if len(members) == 0 {
+				return nil
+			}
+			g.Params.random().Shuffle(len(members), func(i, j int) { members[i], members[j] = members[j], members[i] })
+			g.detector.order = members
+		}
+
+		target := g.detector.order[0]
+		g.detector.order = g.detector.order[1:]
+
+		// members that left our clubs since the shuffle are skipped, the
+		// ones declared dead meanwhile get a last chance to answer
+		if _, ok := g.Peer(target.GetRaw()); ok {
+			return target
+		}
+	}
+}
+
+func (g *Geminus) ping(ctx context.Context, target Addressing.Addr) bool {
+	response, err := g.request(ctx, target.GetRaw(), Peer.NewMessage(Peer.Ping, g.Addr.GetRaw()))
+	return err == nil && response.Type == Peer.Pong
+}
+
+// pingIndirect asks other healthy members to ping target, and waits for the
+// first of them to relay its Pong.
+func (g *Geminus) pingIndirect(ctx context.Context, target Addressing.Addr) bool {
+	helpers := make([]Addressing.Addr, 0, g.indirectProbes())
+	for _, v := range g.GetState() {
+		if len(helpers) == cap(helpers) {
+			break
+		}
+		if liveness, _ := g.Liveness(v.GetRaw()); !isAddr(v, target) && liveness == Alive {
+			helpers = append(helpers, v)
+		}
+	}
+	if len(helpers) == 0 {
+		return false
+	}
+
+	m := Peer.NewMessage(Peer.Ping, g.Addr.GetRaw())
+	m.Destination = target.GetRaw()
+	g.sign(m)
+
+	answer := g.deliveries.expect(m.Nonce)
+	defer g.deliveries.done(m.Nonce)
+
+	payload := Peer.Encode(m)
+	for _, v := range helpers {
+		g.Transport.Send(Addressing.Endpoint(v.GetRaw()), payload)
+	}
+
+	// helpers first wait for their own ping to time out
+	timeout := time.NewTimer(2 * g.requestTimeout())
+	defer timeout.Stop()
+
+	select {
+	case response := <-answer:
+		return response.Type == Peer.Pong
+	case <-timeout.C:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// answerPing pongs a direct ping at once. An indirect one is relayed to its
+// destination, and the sender only gets a Pong if the destination answered.
+// Only club members are relayed to, at most IndirectRelays at a time, so
+// nobody gets the node to ping arbitrary endpoints or to pile up relays;
+// the others go unanswered, which the prober takes as a failed probe.
+func (g *Geminus) answerPing(ctx context.Context, m *Peer.Message) {
+	pong := Peer.NewMessage(Peer.Pong, g.Addr.GetRaw())
+	pong.Nonce = m.Nonce
+
+	if m.Destination == "" || isAddr(g.newAddress(m.Destination), g.Addr) {
+		g.sign(pong)
+		g.Transport.Send(Addressing.Endpoint(m.Sender), Peer.Encode(pong))
+		return
+	}
+
+	if info, known := g.Peer(m.Destination); !known || info.Liveness == Dead || !g.startRelay() {
+		return
+	}
+
+	go func() {
+		defer g.endRelay()
+
+		target := g.newAddress(m.Destination)
+		if !g.ping(ctx, target) {
+			return
+		}
+		g.setLiveness(target, Alive)
+
+		g.sign(pong)
+		g.Transport.Send(Addressing.Endpoint(m.Sender), Peer.Encode(pong))
+	}()
+}
+
+func (g *Geminus) startRelay() bool {
+	g.detector.mu.Lock()
+	defer g.detector.mu.Unlock()
+
+	if g.detector.relays >= g.indirectRelays() {
+		return false
+	}
+	g.detector.relays++
+	return true
+}
+
+func (g *Geminus) endRelay() {
+	g.detector.mu.Lock()
+	g.detector.relays--
+	g.detector.mu.Unlock()
+}
+
+// expireSuspects declares dead the members suspected for longer than
+// SuspicionTimeout and evicts them from every club, keeping their record,
+// and forgets the peers dead for longer than TombstoneTTL.
+func (g *Geminus) expireSuspects() {
+	deadline := time.Now().Add(-g.suspicionTimeout())
+	forgotten := deadline.Add(-g.tombstoneTTL())
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	dead := make(map[string]bool)
+	for id, info := range g.peers {
+		if info.Liveness == Dead && info.SuspectSince.Before(forgotten) {
+			delete(g.peers, id)
+		}
+		if info.Liveness == Suspect && info.SuspectSince.Before(deadline) {
+			dead[id] = true
+		}
+	}
+	if len(dead) == 0 {
+		return
+	}
+
+	clubs := g.copyClubs()
+	for club, members := range clubs {
+		kept := make([]Addressing.Addr, 0, len(members))
+		for _, v := range members {
+			if !dead[string(v.GetHash())] {
+				kept = append(kept, v)
+			}
+		}
+		clubs[club] = kept
+	}
+	for id := range dead {
+		info := g.peers[id]
+		info.Liveness = Dead
+		g.peers[id] = info
+	}
+
+	g.publish(clubs)
+	g.publishUnhealthy()
+}
+
+// setLiveness records the outcome of a probe of a club member. Being
+// suspected again does not restart the suspicion timeout, nor does it bring
+// back a dead peer. A dead peer answering is forgotten, so it can be added
+// to our clubs again.
+func (g *Geminus) setLiveness(v Addressing.Addr, liveness Liveness) {
+	id := string(v.GetHash())
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	info, ok := g.peers[id]
+	if !ok || (liveness == Suspect && info.Liveness != Alive) {
+		return
+	}
+
+	if info.Liveness == Dead {
+		delete(g.peers, id)
+		g.publishUnhealthy()
+		return
+	}
+
+	if liveness == Alive {
+		info.LastSeen = time.Now()
+	} else {
+		info.SuspectSince = time.Now()
+	}
+
+	changed := info.Liveness != liveness
+	info.Liveness = liveness
+	g.peers[id] = info
+
+	if changed {
+		g.publishUnhealthy()
+	}
+}
+
+// dead tells whether v was declared dead.
+func (g *Geminus) dead(v Addressing.Addr) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.peers[string(v.GetHash())].Liveness == Dead
+}
+
+// publishUnhealthy publishes the members routing has to skip; callers hold
+// g.mu.
+func (g *Geminus) publishUnhealthy() {
+	unhealthy := make(map[string]bool)
+	for id, info := range g.peers {
+		if info.Liveness == Suspect || info.Liveness == Dead {
+			unhealthy[id] = true
+		}
+	}
+	g.detector.unhealthy.Store(unhealthy)
+}
+
+func (g *Geminus) probeInterval() time.Duration {
+	if g.Params.ProbeInterval > 0 {
+		return g.Params.ProbeInterval
+	}
+	return DefaultProbeInterval
+}
+
+func (g *Geminus) suspicionTimeout() time.Duration {
+	if g.Params.SuspicionTimeout > 0 {
+		return g.Params.SuspicionTimeout
+	}
+	return DefaultSuspicionTimeout
+}
+
+func (g *Geminus) indirectRelays() int {
+	if g.Params.IndirectRelays > 0 {
+		return g.Params.IndirectRelays
+	}
+	return DefaultIndirectRelays
+}
+
+func (g *Geminus) indirectProbes() int {
+	if g.Params.IndirectProbes > 0 {
+		return g.Params.IndirectProbes
+	}
+	return DefaultIndirectProbes
+}
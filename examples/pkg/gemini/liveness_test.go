@@ -0,0 +1,161 @@
+package gemini
+
+import (
+	"context"
+	Addressing "gemelos/pkg/addressing"
+	Peer "gemelos/pkg/peer"
+	"testing"
+	"time"
+)
+
+func TestFailureDetector(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	nodes := newMemoryNodes(ctx, 100, func() *GeminiConfig {
+		gParams := NewGeminiConfig(100, 160, 3, 3)
+		gParams.RequestTimeout = 50 * time.Millisecond
+		gParams.SuspicionTimeout = 200 * time.Millisecond
+		return gParams
+	})
+
+	g := nodes[0]
+	hat, _ := g.GetClub(Hat)
+	if len(hat) == 0 {
+		t.Fatal("The node should start out with Hat members")
+	}
+
+	var crashed *Geminus
+	for _, other := range nodes {
+		if isAddr(other.Addr, hat[0]) {
+			crashed = other
+		}
+	}
+	crashed.Transport.Close()
+
+	// a round probes every member once
+	members := len(g.GetState())
+	for i := 0; i < members; i++ {
+		if err := g.Probe(ctx); err != nil {
+			t.Fatal("Faulty probe", err)
+		}
+	}
+
+	if liveness, ok := g.Liveness(crashed.Addr.GetRaw()); !ok || liveness != Suspect {
+		t.Log("A member nobody reaches should be suspected", liveness, ok)
+		t.Fail()
+	}
+
+	for _, v := range g.GetState() {
+		if liveness, _ := g.Liveness(v.GetRaw()); !isAddr(v, crashed.Addr) && liveness != Alive {
+			t.Log("A member answering probes should stay alive", v.GetRaw(), liveness)
+			t.Fail()
+		}
+	}
+
+	if next, _ := g.Route(crashed.Addr.GetRaw()); next != nil && isAddr(next, crashed.Addr) {
+		t.Log("Routing should skip suspected members")
+		t.Fail()
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	g.Probe(ctx)
+
+	if liveness, ok := g.Liveness(crashed.Addr.GetRaw()); !ok || liveness != Dead || g.SearchState(Hat, crashed.Addr.GetRaw()) != nil {
+		t.Log("A member suspected for too long should be declared dead and evicted", liveness, ok)
+		t.Fail()
+	}
+
+	if next, _ := g.Route(crashed.Addr.GetRaw()); next != nil && isAddr(next, crashed.Addr) {
+		t.Log("Routing should skip dead peers")
+		t.Fail()
+	}
+
+	if _, err := g.SetState(crashed.Addr.GetRaw()); err != ErrPeerDead {
+		t.Log("Gossip should not bring a dead peer back", err)
+		t.Fail()
+	}
+	if err := g.AddInClub(Hat, crashed.Addr); err != ErrPeerDead {
+		t.Log("A dead peer should not be added back", err)
+		t.Fail()
+	}
+	if err, _ := g.AddAllInClub(Hat, []Addressing.Addr{crashed.Addr}).(*AddError); err == nil || err.Errs[0] != ErrPeerDead {
+		t.Log("A dead peer should not be added back at once either", err)
+		t.Fail()
+	}
+
+	g.revive(crashed.Addr.GetRaw())
+	if _, err := g.SetState(crashed.Addr.GetRaw()); err != nil || g.SearchState(Hat, crashed.Addr.GetRaw()) == nil {
+		t.Log("A dead peer joining again should be taken back", err)
+		t.Fail()
+	}
+	if liveness, _ := g.Liveness(crashed.Addr.GetRaw()); liveness != Alive {
+		t.Log("A dead peer taken back should be alive", liveness)
+		t.Fail()
+	}
+
+	if _, ok := g.Liveness(nodes[0].Addr.GetRaw()); ok {
+		t.Log("The node is not a member of its own clubs")
+		t.Fail()
+	}
+}
+
+func TestIndirectProbe(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	nodes := newMemoryNodes(ctx, 20, func() *GeminiConfig {
+		gParams := NewGeminiConfig(20, 160, 1, 1)
+		gParams.RequestTimeout = 50 * time.Millisecond
+		return gParams
+	})
+
+	g := nodes[0]
+	target := g.GetState()[0]
+
+	if !g.pingIndirect(ctx, target) {
+		t.Log("Other members should reach the target for us")
+		t.Fail()
+	}
+}
+
+func TestIndirectProbeRelaysToMembersOnly(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	network := Peer.NewMemoryNetwork()
+	newNode := func(addr string) *Geminus {
+		transport := network.NewTransport()
+		transport.Listen(addr)
+
+		gParams := NewGeminiConfig(3, 160, 3, 3)
+		gParams.RequestTimeout = 50 * time.Millisecond
+		g := NewGeminus(addr, gParams)
+		g.Init()
+		g.Transport = transport
+		go g.Serve(ctx)
+		return g
+	}
+
+	g, helper, stranger := newNode("10.80.0.1"), newNode("10.80.0.2"), newNode("10.80.0.3")
+	g.AddInClub(Hat, helper.Addr)
+	helper.AddInClub(Hat, g.Addr)
+
+	if g.pingIndirect(ctx, stranger.Addr) {
+		t.Log("A node should not relay probes to peers outside its clubs")
+		t.Fail()
+	}
+
+	helper.AddInClub(Hat, stranger.Addr)
+	if !g.pingIndirect(ctx, stranger.Addr) {
+		t.Log("A node should relay probes to its club members")
+		t.Fail()
+	}
+
+	helper.Params.IndirectRelays = 1
+	if !helper.startRelay() || helper.startRelay() {
+		t.Log("A node should bound the probes it relays at once")
+		t.Fail()
+	}
+	helper.endRelay()
+}
@@ -0,0 +1,199 @@
+package gemini
+
+import (
+	"context"
+	"errors"
+	Addressing "gemelos/pkg/addressing"
+	Peer "gemelos/pkg/peer"
+	"time"
+)
+
+const DefaultRequestTimeout = 2 * time.Second
+
+var (
+	ErrNoAnswer  = errors.New("Peer did not answer the request")
+	ErrRevisited = errors.New("Peer answered with a hop the lookup already avoids")
+)
+
+// LookupStep is one question of an iterative lookup: which peer was asked
+// and what it answered. The last step of a lookup either has Found set or
+// carries a *DeliveryError telling why the lookup gave up.
+type LookupStep struct {
+	Hop    string
+	Next   string
+	Status RoutingStatus
+	Found  bool
+	Err    error
+}
+
+// Lookup resolves destination iteratively: rather than forwarding, the
+// node asks every hop on the way for its next hop and contacts that one
+// itself. A hop that does not answer, or answers with one already visited,
+// is avoided from then on and the previous hop is asked again, so
+// misbehaving peers are routed around. Every step is reported on the
+// returned channel, which is closed once the lookup is over.
+func (g *Geminus) Lookup(ctx context.Context, destination string) <-chan LookupStep {
+	steps := make(chan LookupStep)
+
+	go func() {
+		defer close(steps)
+
+		emit := func(step LookupStep) bool {
+			select {
+			case steps <- step:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		haddr := g.newAddress(destination)
+		if isAddr(haddr, g.Addr) {
+			emit(LookupStep{Hop: g.Addr.GetRaw(), Next: g.Addr.GetRaw(), Found: true})
+			return
+		}
+
+		path := []Addressing.Addr{g.Addr}
+		avoid := []string{g.Addr.GetRaw()}
+		avoided := map[string]bool{string(g.Addr.GetHash()): true}
+		avoidHop := func(hop Addressing.Addr) {
+			if !avoided[string(hop.GetHash())] {
+				avoided[string(hop.GetHash())] = true
+				avoid = append(avoid, hop.GetRaw())
+			}
+		}
+
+		for asked := uint32(0); ; asked++ {
+			if len(path) == 0 || asked >= g.ttl() {
+				var reason DeliveryFailure = NoRoute
+				if len(path) > 0 {
+					reason = TTLExceeded
+				}
+				emit(LookupStep{Err: &DeliveryError{Reason: reason, Hop: g.Addr.GetRaw(), Hops: int(asked)}})
+				return
+			}
+
+			hop := path[len(path)-1]
+			next, status, err := g.askRoute(ctx, hop, destination, avoid)
+			step := LookupStep{Hop: hop.GetRaw(), Status: status, Err: err}
+
+			switch {
+			case ctx.Err() != nil:
+				return
+
+			case err != nil || next == nil:
+				// a dead end or a silent hop, back off to the previous one
+				path = path[:len(path)-1]
+				avoidHop(hop)
+
+			case isAddr(next, haddr):
+				step.Next = next.GetRaw()
+				step.Found = true
+				emit(step)
+				return
+
+			case avoided[string(next.GetHash())]:
+				step.Next = next.GetRaw()
+				step.Err = ErrRevisited
+				path = path[:len(path)-1]
+				avoidHop(hop)
+
+			default:
+				step.Next = next.GetRaw()
+				path = append(path, next)
+				avoidHop(next)
+			}
+
+			if !emit(step) {
+				return
+			}
+		}
+	}()
+
+	return steps
+}
+
+// askRoute asks hop for its next hop towards destination, answering
+// locally when the hop is ourselves.
+func (g *Geminus) askRoute(ctx context.Context, hop Addressing.Addr, destination string, avoid []string) (Addressing.Addr, RoutingStatus, error) {
+	if hop == g.Addr {
+		next, status := g.RouteAvoiding(destination, avoid...)
+		return next, status, nil
+	}
+
+	request := Peer.NewMessage(Peer.RouteRequest, g.Addr.GetRaw())
+	request.Destination = destination
+	request.Peers = avoid
+
+	response, err := g.request(ctx, hop.GetRaw(), request)
+	if err != nil {
+		return nil, Undefined, err
+	}
+	if response.Type != Peer.RouteResponse || !isAddr(g.newAddress(response.Sender), hop) {
+		return nil, Undefined, ErrNoAnswer
+	}
+
+	if len(response.Peers) == 0 {
+		return nil, RoutingStatus(response.Payload), nil
+	}
+	return g.newAddress(response.Peers[0]), RoutingStatus(response.Payload), nil
+}
+
+// answerRoute is the other side of askRoute.
+func (g *Geminus) answerRoute(request *Peer.Message) {
+	response := Peer.NewMessage(Peer.RouteResponse, g.Addr.GetRaw())
+	response.Nonce = request.Nonce
+	response.Destination = request.Destination
+
+	next, status := g.RouteAvoiding(request.Destination, request.Peers...)
+	if next != nil {
+		response.Peers = []string{next.GetRaw()}
+	}
+	response.Payload = []byte(status)
+	g.sign(response)
+
+	g.Transport.Send(Addressing.Endpoint(request.Sender), Peer.Encode(response))
+}
+
+// request signs and sends m to the endpoint of to, then waits for the
+// answer echoing its nonce.
+func (g *Geminus) request(ctx context.Context, to string, m *Peer.Message) (*Peer.Message, error) {
+	if g.Transport == nil {
+		return nil, ErrNoTransport
+	}
+
+	g.sign(m)
+
+	answer := g.deliveries.expect(m.Nonce)
+	defer g.deliveries.done(m.Nonce)
+
+	if err := g.Transport.Send(Addressing.Endpoint(to), Peer.Encode(m)); err != nil {
+		return nil, err
+	}
+
+	timeout := time.NewTimer(g.requestTimeout())
+	defer timeout.Stop()
+
+	select {
+	case response := <-answer:
+		return response, nil
+	case <-timeout.C:
+		return nil, ErrNoAnswer
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (g *Geminus) ttl() uint32 {
+	if g.Params.TTL > 0 {
+		return g.Params.TTL
+	}
+	return Peer.DefaultTTL
+}
+
+func (g *Geminus) requestTimeout() time.Duration {
+	if g.Params.RequestTimeout > 0 {
+		return g.Params.RequestTimeout
+	}
+	return DefaultRequestTimeout
+}
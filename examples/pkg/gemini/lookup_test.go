@@ -0,0 +1,130 @@
+package gemini
+
+import (
+	"context"
+	Addressing "gemelos/pkg/addressing"
+	Peer "gemelos/pkg/peer"
+	"testing"
+	"time"
+)
+
+func collect(steps <-chan LookupStep) []LookupStep {
+	all := make([]LookupStep, 0)
+	for step := range steps {
+		all = append(all, step)
+	}
+	return all
+}
+
+func TestLookup(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	nodes := newMemoryNodes(ctx, 300, func() *GeminiConfig { return NewGeminiConfig(300, 160, 3, 3) })
+
+	for i := 1; i < 50; i++ {
+		destination := nodes[(i*7)%len(nodes)].Addr.GetRaw()
+		steps := collect(nodes[0].Lookup(ctx, destination))
+
+		last := steps[len(steps)-1]
+		if !last.Found || last.Next != destination {
+			t.Log("Lookup did not find its destination", last)
+			t.Fail()
+			continue
+		}
+
+		if steps[0].Hop != nodes[0].Addr.GetRaw() {
+			t.Log("Lookup should start from the node itself", steps[0])
+			t.Fail()
+		}
+
+		for j := 1; j < len(steps); j++ {
+			if steps[j].Err == nil && steps[j-1].Err == nil && steps[j].Hop != steps[j-1].Next {
+				t.Log("Every step should ask the hop the previous one answered", steps[j-1], steps[j])
+				t.Fail()
+			}
+		}
+	}
+}
+
+// preferStrategy answers with the destination when it is known, and the
+// first known peer of its list otherwise.
+type preferStrategy []Addressing.Addr
+
+func (order preferStrategy) Next(s *ClubState, destination Addressing.Addr) (Addressing.Addr, RoutingStatus) {
+	candidates := append([]Addressing.Addr{destination}, order...)
+	for _, candidate := range candidates {
+		for _, members := range s.Clubs {
+			for _, v := range members {
+				if isAddr(v, candidate) {
+					return v, RandomForward
+				}
+			}
+		}
+	}
+	return nil, Undefined
+}
+
+func TestLookupAvoidsSilentHops(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	network := Peer.NewMemoryNetwork()
+	newNode := func(addr string, serve bool) *Geminus {
+		transport := network.NewTransport()
+		transport.Listen(addr)
+
+		gParams := NewGeminiConfig(6000, 160, 3, 3)
+		gParams.RequestTimeout = 100 * time.Millisecond
+
+		g := NewGeminus(addr, gParams)
+		g.Init()
+		g.Transport = transport
+		if serve {
+			go g.Serve(ctx)
+		}
+		return g
+	}
+
+	a := newNode("10.90.1.1", true)
+	silent := newNode("10.90.1.2", false)
+	c := newNode("10.90.1.3", true)
+	d := newNode("10.90.1.4", true)
+
+	a.AddInClub(Hat, silent.Addr)
+	a.AddInClub(Hat, c.Addr)
+	c.AddInClub(Hat, d.Addr)
+	a.Params.Strategy = preferStrategy{silent.Addr, c.Addr}
+	c.Params.Strategy = preferStrategy{}
+
+	steps := collect(a.Lookup(ctx, d.Addr.GetRaw()))
+
+	expected := []LookupStep{
+		{Hop: a.Addr.GetRaw(), Next: silent.Addr.GetRaw()},
+		{Hop: silent.Addr.GetRaw(), Err: ErrNoAnswer},
+		{Hop: a.Addr.GetRaw(), Next: c.Addr.GetRaw()},
+		{Hop: c.Addr.GetRaw(), Next: d.Addr.GetRaw(), Found: true},
+	}
+
+	if len(steps) != len(expected) {
+		t.Fatal("Lookup should route around the silent hop", steps)
+	}
+
+	for i, step := range steps {
+		if step.Hop != expected[i].Hop || step.Next != expected[i].Next || step.Found != expected[i].Found || step.Err != expected[i].Err {
+			t.Log("Unexpected lookup step", step, "instead of", expected[i])
+			t.Fail()
+		}
+	}
+
+	c.Params.Strategy = preferStrategy{a.Addr}
+	c.AddInClub(Hat, a.Addr)
+	c.RemoveFromClub(Hat, d.Addr)
+
+	steps = collect(a.Lookup(ctx, d.Addr.GetRaw()))
+	last := steps[len(steps)-1]
+	if failure, ok := last.Err.(*DeliveryError); !ok || failure.Reason != NoRoute {
+		t.Log("A lookup with nowhere left to go should end with NoRoute", last)
+		t.Fail()
+	}
+}
@@ -0,0 +1,65 @@
+package gemini
+
+import (
+	"math"
+)
+
+// Club sizing assumes ids spread uniformly over the ring: a case of L bits
+// splits the network into 2^L clubs, and how many of the NetworkCapacity
+// nodes land in one club follows a binomial distribution.
+
+// MaxClubSizeDeviations is how many standard deviations above the expected
+// size a club may grow before eviction kicks in.
+const MaxClubSizeDeviations = 3
+
+// caseCount is the number of distinct values of a case, 2^L.
+func (gc *GeminiConfig) caseCount(club Club) (float64, bool) {
+	cd, ok := gc.Case(club)
+	if !ok {
+		return 0, false
+	}
+	return math.Ldexp(1, cd.Length), true
+}
+
+// ExpectedClubSize is the average number of nodes sharing a case value.
+func (gc *GeminiConfig) ExpectedClubSize(club Club) float64 {
+	k, ok := gc.caseCount(club)
+	if !ok {
+		return 0
+	}
+	return float64(gc.NetworkCapacity) / k
+}
+
+// MaxClubSize bounds a club MaxClubSizeDeviations standard deviations above
+// its expected size, so that only unlucky clubs ever evict.
+func (gc *GeminiConfig) MaxClubSize(club Club) int {
+	k, ok := gc.caseCount(club)
+	if !ok {
+		return 0
+	}
+
+	mu := float64(gc.NetworkCapacity) / k
+	sigma := math.Sqrt(mu * (1 - 1/k))
+
+	return int(math.Max(1, math.Ceil(mu+MaxClubSizeDeviations*sigma)))
+}
+
+// ExpectedClubCount is the number of case values held by at least one of
+// the nodes, 2^L (1 - (1 - 2^-L)^N).
+func (gc *GeminiConfig) ExpectedClubCount(club Club) float64 {
+	k, ok := gc.caseCount(club)
+	if !ok {
+		return 0
+	}
+	return -k * math.Expm1(float64(gc.NetworkCapacity)*math.Log1p(-1/k))
+}
+
+// ExpectedLonelyIslands is the number of nodes no other node shares the
+// case with, N (1 - 2^-L)^(N-1). Those nodes have an empty club.
+func (gc *GeminiConfig) ExpectedLonelyIslands(club Club) float64 {
+	k, ok := gc.caseCount(club)
+	if !ok || gc.NetworkCapacity <= 0 {
+		return 0
+	}
+	return float64(gc.NetworkCapacity) * math.Exp(float64(gc.NetworkCapacity-1)*math.Log1p(-1/k))
+}
@@ -0,0 +1,65 @@
+package gemini
+
+import (
+	"fmt"
+	Addressing "gemelos/pkg/addressing"
+	"math"
+	"testing"
+)
+
+func TestClubSizeFollowsCaseLength(t *testing.T) {
+	gParams := NewGeminiConfig(6000, 160, 3, 5)
+
+	if gParams.ExpectedClubSize(Hat) != 750 || gParams.ExpectedClubSize(Boot) != 187.5 {
+		t.Log("Faulty expected club sizes", gParams.ExpectedClubSize(Hat), gParams.ExpectedClubSize(Boot))
+		t.Fail()
+	}
+
+	if gParams.ClubSize[Hat] != 827 || gParams.ClubSize[Boot] != 228 {
+		t.Log("Club sizes should allow three standard deviations above the expected size", gParams.ClubSize)
+		t.Fail()
+	}
+
+	if gParams.MaxClubSize(Unrecognized) != 0 || gParams.ExpectedClubCount(Unrecognized) != 0 {
+		t.Log("Unknown clubs should not be sized")
+		t.Fail()
+	}
+}
+
+func TestExpectedClubCountAndLonelyIslands(t *testing.T) {
+	const capacity = 6000
+	gParams := NewGeminiConfig(capacity, 160, 3, 12)
+
+	if math.Abs(gParams.ExpectedClubCount(Hat)-8) > 1e-9 {
+		t.Log("Every Hat club should be expected to exist", gParams.ExpectedClubCount(Hat))
+		t.Fail()
+	}
+
+	if gParams.ExpectedLonelyIslands(Hat) > 1e-9 {
+		t.Log("No node should be expected alone in a Hat club", gParams.ExpectedLonelyIslands(Hat))
+		t.Fail()
+	}
+
+	counts := make(map[Addressing.Case]int)
+	for i := 0; i < capacity; i++ {
+		haddr := Addressing.NewAddress(fmt.Sprintf("10.20.%d.%d", i/256, i%256), true)
+		counts[haddr.Suffix(12)]++
+	}
+
+	lonely := 0
+	for _, count := range counts {
+		if count == 1 {
+			lonely++
+		}
+	}
+
+	if expected := gParams.ExpectedClubCount(Boot); math.Abs(float64(len(counts))-expected) > 0.05*expected {
+		t.Log("Boot club count too far from the expected one", len(counts), expected)
+		t.Fail()
+	}
+
+	if expected := gParams.ExpectedLonelyIslands(Boot); math.Abs(float64(lonely)-expected) > 0.1*expected {
+		t.Log("Lonely islands too far from the expected ones", lonely, expected)
+		t.Fail()
+	}
+}
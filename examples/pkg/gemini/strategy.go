@@ -0,0 +1,279 @@
+package gemini
+
+import (
+	bytes "bytes"
+	Addressing "gemelos/pkg/addressing"
+)
+
+const (
+	HeadRoute         RoutingStatus = "Head"
+	SecondHeadRoute                 = "SecondHead"
+	HeadInTails                     = "HeadInTails"
+	HeadInBodies                    = "HeadInBodies"
+	HeadInSecondTails               = "HeadInSecondTails"
+	TailInHeads                     = "TailInHeads"
+	TailInBodies                    = "TailInBodies"
+	BodyInHeads                     = "BodyInHeads"
+	TailInSecondHeads               = "TailInSecondHeads"
+)
+
+type (
+	// RoutingStrategy decides the next hop towards a destination out of the
+	// clubs a node knows, and names the decision it took.
+	RoutingStrategy interface {
+		Next(state *ClubState, destination Addressing.Addr) (Addressing.Addr, RoutingStatus)
+	}
+
+	// ClubState is everything a strategy gets to look at: the local node
+	// and its clubs, never the rest of the network.
+	ClubState struct {
+		Params *GeminiConfig
+		Self   Addressing.Addr
+		Clubs  map[Club][]Addressing.Addr
+	}
+
+	// DefaultStrategy works with any case layout. It converges through the
+	// club of the first case: a destination sharing it is reached through
+	// the numerically closest member, otherwise any other club member
+	// sharing the destination's first case is forwarded to (BootForward).
+	// Failing that it steps to the known peer closest to the destination on
+	// the ring, if closer than us (RingForward), which is how destinations
+	// alone in their first case are reached, and as a last resort to a
+	// random member of our own first club that shares none of the
+	// destination's other cases.
+	DefaultStrategy struct{}
+
+	// HatBootStrategy is the two dimensional Hat/HatInBoot/ABootInHat
+	// routing of the simulations, reported as HatRoute, BootForward and
+	// RandomForward. It needs the Hat and Boot clubs.
+	HatBootStrategy struct{}
+
+	// HeadBodyTailStrategy is the three dimensional routing, it needs the
+	// Head, Body and Tail clubs.
+	HeadBodyTailStrategy struct{}
+
+	// HeadTailReversedStrategy is the four dimensional routing, it needs
+	// the Head, Tail, ReversedHead and ReversedTail clubs.
+	HeadTailReversedStrategy struct{}
+)
+
+// Case returns the bits of haddr that make up the given case, or false if
+// the layout has no such case.
+func (s *ClubState) Case(club Club, haddr Addressing.Addr) (Addressing.Case, bool) {
+	cd, ok := s.Params.Case(club)
+	if !ok {
+		return Addressing.Case{}, false
+	}
+	return cd.Of(haddr), true
+}
+
+// SameCase reports whether a and b share the given case.
+func (s *ClubState) SameCase(club Club, a, b Addressing.Addr) bool {
+	caseA, okA := s.Case(club, a)
+	caseB, okB := s.Case(club, b)
+	return okA && okB && caseA == caseB
+}
+
+// Closest returns the member of a club numerically closest to haddr.
+func (s *ClubState) Closest(club Club, haddr Addressing.Addr) Addressing.Addr {
+	var closest Addressing.Addr
+
+	for _, v := range s.Clubs[club] {
+		if closest == nil || s.Params.Ring.Closer(v.GetHash(), closest.GetHash(), haddr.GetHash()) {
+			closest = v
+		}
+	}
+
+	return closest
+}
+
+// Without returns a copy of the state minus the peers exclude matches.
+func (s *ClubState) Without(exclude func(Addressing.Addr) bool) *ClubState {
+	clubs := make(map[Club][]Addressing.Addr, len(s.Clubs))
+	for club, members := range s.Clubs {
+		kept := make([]Addressing.Addr, 0, len(members))
+		for _, v := range members {
+			if !exclude(v) {
+				kept = append(kept, v)
+			}
+		}
+		clubs[club] = kept
+	}
+
+	return &ClubState{Params: s.Params, Self: s.Self, Clubs: clubs}
+}
+
+// Nearer returns the peer, in any club, closest to haddr on the ring,
+// provided it is closer to it than the node itself.
+func (s *ClubState) Nearer(haddr Addressing.Addr) Addressing.Addr {
+	var nearest Addressing.Addr
+
+	for _, members := range s.Clubs {
+		for _, v := range members {
+			if nearest == nil || s.Params.Ring.Closer(v.GetHash(), nearest.GetHash(), haddr.GetHash()) {
+				nearest = v
+			}
+		}
+	}
+
+	if nearest == nil || !s.Params.Ring.Closer(nearest.GetHash(), s.Self.GetHash(), haddr.GetHash()) {
+		return nil
+	}
+	return nearest
+}
+
+// First returns the first member of a club accepted by match.
+func (s *ClubState) First(club Club, match func(Addressing.Addr) bool) Addressing.Addr {
+	for _, v := range s.Clubs[club] {
+		if match(v) {
+			return v
+		}
+	}
+	return nil
+}
+
+// Random picks random members of a club until match accepts one, giving up
+// after as many picks as there are members. Picks draw from Params.Source.
+func (s *ClubState) Random(club Club, match func(Addressing.Addr) bool) Addressing.Addr {
+	members := s.Clubs[club]
+	random := s.Params.random()
+
+	for tries := 0; tries < len(members); tries++ {
+		candidate := members[random.Intn(len(members))]
+		if match(candidate) {
+			return candidate
+		}
+	}
+	return nil
+}
+
+func isAddr(a, b Addressing.Addr) bool {
+	return bytes.Equal(a.GetHash(), b.GetHash())
+}
+
+func (DefaultStrategy) Next(s *ClubState, haddr Addressing.Addr) (Addressing.Addr, RoutingStatus) {
+	primary := s.Params.primary()
+
+	if primary.Of(s.Self) == primary.PeerCase(haddr) {
+		if found := s.Closest(primary.Name, haddr); found != nil {
+			if isAddr(found, haddr) {
+				return found, HatRoute
+			}
+			return found, HatClosestForward
+		}
+	}
+
+	for _, cd := range s.Params.Cases[1:] {
+		found := s.First(cd.Name, func(v Addressing.Addr) bool {
+			return primary.Of(v) == primary.Of(haddr)
+		})
+		if found != nil {
+			return found, BootForward
+		}
+	}
+
+	if found := s.Nearer(haddr); found != nil {
+		return found, RingForward
+	}
+
+	found := s.Random(primary.Name, func(v Addressing.Addr) bool {
+		for _, cd := range s.Params.Cases[1:] {
+			if !cd.Reversed && cd.Of(v) == cd.Of(haddr) {
+				return false
+			}
+		}
+		return true
+	})
+	if found != nil {
+		return found, RandomForward
+	}
+
+	return nil, Undefined
+}
+
+func (HatBootStrategy) Next(s *ClubState, haddr Addressing.Addr) (Addressing.Addr, RoutingStatus) {
+	if s.SameCase(Hat, s.Self, haddr) {
+		if found := s.Closest(Hat, haddr); found != nil {
+			if isAddr(found, haddr) {
+				return found, HatRoute
+			}
+			return found, HatClosestForward
+		}
+	}
+
+	sameHat := func(v Addressing.Addr) bool { return s.SameCase(Hat, v, haddr) }
+	if found := s.First(Boot, sameHat); found != nil {
+		return found, BootForward
+	}
+
+	otherBoot := func(v Addressing.Addr) bool { return !s.SameCase(Boot, v, haddr) }
+	if found := s.Random(Hat, otherBoot); found != nil {
+		return found, RandomForward
+	}
+
+	return nil, Undefined
+}
+
+func (HeadBodyTailStrategy) Next(s *ClubState, haddr Addressing.Addr) (Addressing.Addr, RoutingStatus) {
+	if s.SameCase(Head, s.Self, haddr) {
+		if found := s.Closest(Head, haddr); found != nil {
+			return found, HeadRoute
+		}
+	}
+
+	sameHead := func(v Addressing.Addr) bool { return s.SameCase(Head, v, haddr) }
+	if found := s.First(Tail, sameHead); found != nil {
+		return found, HeadInTails
+	}
+	if found := s.First(Body, sameHead); found != nil {
+		return found, HeadInBodies
+	}
+
+	otherTail := func(v Addressing.Addr) bool { return !s.SameCase(Tail, v, haddr) }
+	if found := s.Random(Body, otherTail); found != nil {
+		return found, TailInBodies
+	}
+	if found := s.Random(Head, otherTail); found != nil {
+		return found, TailInHeads
+	}
+
+	otherBody := func(v Addressing.Addr) bool { return !s.SameCase(Body, v, haddr) }
+	if found := s.Random(Head, otherBody); found != nil {
+		return found, BodyInHeads
+	}
+
+	return nil, Undefined
+}
+
+func (HeadTailReversedStrategy) Next(s *ClubState, haddr Addressing.Addr) (Addressing.Addr, RoutingStatus) {
+	if s.SameCase(Head, s.Self, haddr) {
+		if found := s.Closest(Head, haddr); found != nil {
+			return found, HeadRoute
+		}
+	}
+
+	// our reversed tail club holds the peers whose head is our tail
+	if rtail, ok := s.Params.Case(ReversedTail); ok && rtail.Of(s.Self) == rtail.PeerCase(haddr) {
+		if found := s.Closest(ReversedTail, haddr); found != nil {
+			return found, SecondHeadRoute
+		}
+	}
+
+	sameHead := func(v Addressing.Addr) bool { return s.SameCase(Head, v, haddr) }
+	if found := s.First(Tail, sameHead); found != nil {
+		return found, HeadInTails
+	}
+	if found := s.First(ReversedHead, sameHead); found != nil {
+		return found, HeadInSecondTails
+	}
+
+	otherTail := func(v Addressing.Addr) bool { return !s.SameCase(Tail, v, haddr) }
+	if found := s.Random(Head, otherTail); found != nil {
+		return found, TailInHeads
+	}
+	if found := s.Random(ReversedTail, otherTail); found != nil {
+		return found, TailInSecondHeads
+	}
+
+	return nil, Undefined
+}
@@ -0,0 +1,170 @@
+package gemini
+
+import (
+	"fmt"
+	Addressing "gemelos/pkg/addressing"
+	Tools "gemelos/pkg/tools"
+	"testing"
+)
+
+// id builds a hashed 16 bit address straight from its bits.
+func id(bits uint16) Addressing.Addr {
+	return &Addressing.Address{
+		Hashed: []byte{byte(bits >> 8), byte(bits)},
+		Status: Addressing.Hashed,
+	}
+}
+
+func newClubState(cases []CaseDefinition, self Addressing.Addr, members ...Addressing.Addr) *ClubState {
+	s := &ClubState{
+		Params: NewGeminiConfigWithCases(6000, 16, cases),
+		Self:   self,
+		Clubs:  make(map[Club][]Addressing.Addr),
+	}
+
+	for _, cd := range cases {
+		for _, v := range members {
+			if cd.Of(self) == cd.PeerCase(v) {
+				s.Clubs[cd.Name] = append(s.Clubs[cd.Name], v)
+			}
+		}
+	}
+
+	return s
+}
+
+func TestRandomIsSeeded(t *testing.T) {
+	members := make([]Addressing.Addr, 0, 64)
+	for i := 0; i < cap(members); i++ {
+		members = append(members, id(uint16(i)<<3))
+	}
+
+	picks := func(seed int64) []Addressing.Addr {
+		s := newClubState(DefaultCases(13, 3), id(0), members...)
+		s.Params.Source = Tools.NewSource(seed)
+
+		picked := make([]Addressing.Addr, 0, 20)
+		for len(picked) < cap(picked) {
+			picked = append(picked, s.Random(Boot, func(Addressing.Addr) bool { return true }))
+		}
+		return picked
+	}
+
+	first, again, other := picks(42), picks(42), picks(43)
+	same, differ := true, false
+	for i := range first {
+		same = same && isAddr(first[i], again[i])
+		differ = differ || !isAddr(first[i], other[i])
+	}
+
+	if !same || !differ {
+		t.Log("Random picks should replay from the same seed only", same, differ)
+		t.Fail()
+	}
+}
+
+func TestHatBootStrategy(t *testing.T) {
+	self := id(0x0001)
+	hatPeer := id(0x0f01)
+	bootPeer := id(0xa001)
+
+	s := newClubState(DefaultCases(3, 3), self, hatPeer, bootPeer)
+
+	if found, status := (HatBootStrategy{}).Next(s, hatPeer); status != HatRoute || found != hatPeer {
+		t.Log("Known Hat Club member should be routed to directly", status)
+		t.Fail()
+	}
+
+	if found, status := (HatBootStrategy{}).Next(s, id(0x0e00)); status != HatClosestForward || found != hatPeer {
+		t.Log("Unknown Hat Club address should go to the closest member", status)
+		t.Fail()
+	}
+
+	if found, status := (HatBootStrategy{}).Next(s, id(0xa0f0)); status != BootForward || found != bootPeer {
+		t.Log("Destination Hat should be reached through the Boot Club", status)
+		t.Fail()
+	}
+
+	if found, status := (HatBootStrategy{}).Next(s, id(0x6002)); status != RandomForward || found != hatPeer {
+		t.Log("Unknown destination should be forwarded to a Hat Club member", status)
+		t.Fail()
+	}
+
+	if _, status := (HatBootStrategy{}).Next(s, id(0x6001)); status != Undefined {
+		t.Log("A Hat Club member sharing the destination Boot should not be picked", status)
+		t.Fail()
+	}
+}
+
+func TestHeadBodyTailStrategy(t *testing.T) {
+	cases := HeadBodyTailCases(16, 3, 2, 3)
+
+	self := id(0x0001)
+	tailPeer := id(0x4209)
+	bodyPeer := id(0x8082)
+
+	s := newClubState(cases, self, tailPeer, bodyPeer)
+
+	if found, status := (HeadBodyTailStrategy{}).Next(s, id(0x4ff0)); status != HeadInTails || found != tailPeer {
+		t.Log("Destination Head should be reached through the Tail Club first", status)
+		t.Fail()
+	}
+
+	if found, status := (HeadBodyTailStrategy{}).Next(s, id(0x80f0)); status != HeadInBodies || found != bodyPeer {
+		t.Log("Destination Head should be reached through the Body Club", status)
+		t.Fail()
+	}
+
+	if found, status := (HeadBodyTailStrategy{}).Next(s, id(0x2000)); status != TailInBodies || found != bodyPeer {
+		t.Log("Unknown destination should be forwarded through the Body Club", status)
+		t.Fail()
+	}
+}
+
+func TestHeadTailReversedStrategy(t *testing.T) {
+	cases := HeadTailReversedCases(3, 3)
+
+	// self tail is 010, so the reversed tail club holds heads of 010
+	self := id(0x0002)
+	secondHead := id(0x4001)
+	closerSecondHead := id(0x4f01)
+
+	s := newClubState(cases, self, secondHead, closerSecondHead)
+
+	if len(s.Clubs[ReversedTail]) != 2 {
+		t.Log("Reversed tail club should hold the peers whose head is our tail")
+		t.Fail()
+	}
+
+	if found, status := (HeadTailReversedStrategy{}).Next(s, id(0x5000)); status != SecondHeadRoute || found != closerSecondHead {
+		t.Log("Destination whose head is our tail should go to the closest second head", status)
+		t.Fail()
+	}
+
+	if _, status := (HeadTailReversedStrategy{}).Next(s, id(0xe000)); status != TailInSecondHeads {
+		t.Log("Unknown destination should be forwarded through the second heads", status)
+		t.Fail()
+	}
+}
+
+func TestGeminusStrategy(t *testing.T) {
+	gParams := NewGeminiConfigWithCases(6000, 160, HeadTailReversedCases(3, 3))
+	gParams.Strategy = HeadTailReversedStrategy{}
+
+	g := NewGeminus("10.10.210.21", gParams)
+	g.Init()
+
+	if _, status := g.Route("41.210.412.312"); status != Undefined {
+		t.Log("Empty clubs should leave the route undefined", status)
+		t.Fail()
+	}
+
+	for i := 0; len(g.Clubs[Head]) == 0; i++ {
+		g.SetState(fmt.Sprintf("10.80.%d.%d", i/256, i%256))
+	}
+
+	if _, status := g.Route(g.Clubs[Head][0].GetRaw()); status != HeadRoute {
+		t.Log("Geminus should route with the configured strategy", status)
+		t.Fail()
+	}
+}
@@ -0,0 +1,135 @@
+package peer
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// MaxPeersPerMessage bounds the peer list a decoder accepts.
+const MaxPeersPerMessage = 4096
+
+var (
+	ErrUnsupportedVersion = errors.New("Unsupported wire protocol version")
+	ErrMalformedMessage   = errors.New("Malformed wire message")
+)
+
+// Encode writes m in the version 1 wire format. Every integer is an
+// unsigned varint, every string and byte slice a varint length followed by
+// its bytes, and the fields come in this order:
+//
+//	version type nonce ttl hops sender destination club
+//	len(peers) peers... payload signature
+//
+// The same message always encodes to the same bytes.
+func Encode(m *Message) []byte {
+	buf := make([]byte, 0, 64+len(m.Sender)+len(m.Destination)+len(m.Payload)+len(m.Signature))
+
+	buf = appendUvarint(buf, uint64(m.Version))
+	buf = appendUvarint(buf, uint64(m.Type))
+	buf = appendUvarint(buf, m.Nonce)
+	buf = appendUvarint(buf, uint64(m.TTL))
+	buf = appendUvarint(buf, uint64(m.Hops))
+	buf = appendBytes(buf, []byte(m.Sender))
+	buf = appendBytes(buf, []byte(m.Destination))
+	buf = appendBytes(buf, []byte(m.Club))
+	buf = appendUvarint(buf, uint64(len(m.Peers)))
+	for _, p := range m.Peers {
+		buf = appendBytes(buf, []byte(p))
+	}
+	buf = appendBytes(buf, m.Payload)
+	buf = appendBytes(buf, m.Signature)
+
+	return buf
+}
+
+// Decode parses a message written by Encode. It refuses other versions,
+// truncated input and trailing bytes.
+func Decode(data []byte) (*Message, error) {
+	d := decoder{data: data}
+
+	m := &Message{}
+
+	version := d.uvarint(0xff)
+	if d.err == nil && version != ProtocolVersion {
+		return nil, ErrUnsupportedVersion
+	}
+	m.Version = uint8(version)
+	m.Type = MessageType(d.uvarint(0xff))
+	m.Nonce = d.uvarint(1<<64 - 1)
+	m.TTL = uint32(d.uvarint(1<<32 - 1))
+	m.Hops = uint32(d.uvarint(1<<32 - 1))
+	m.Sender = string(d.bytes())
+	m.Destination = string(d.bytes())
+	m.Club = string(d.bytes())
+
+	if count := d.uvarint(MaxPeersPerMessage); count > 0 {
+		m.Peers = make([]string, 0, count)
+		for i := uint64(0); i < count && d.err == nil; i++ {
+			m.Peers = append(m.Peers, string(d.bytes()))
+		}
+	}
+
+	m.Payload = d.bytes()
+	m.Signature = d.bytes()
+
+	if d.err == nil && len(d.data) != 0 {
+		d.err = ErrMalformedMessage
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	if _, ok := messageTypes[m.Type]; !ok {
+		return nil, ErrMalformedMessage
+	}
+
+	return m, nil
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	var scratch [binary.MaxVarintLen64]byte
+	return append(buf, scratch[:binary.PutUvarint(scratch[:], v)]...)
+}
+
+func appendBytes(buf, b []byte) []byte {
+	buf = appendUvarint(buf, uint64(len(b)))
+	return append(buf, b...)
+}
+
+// decoder consumes data field by field and remembers the first error, so
+// Decode can check it once at the end.
+type decoder struct {
+	data []byte
+	err  error
+}
+
+func (d *decoder) uvarint(max uint64) uint64 {
+	if d.err != nil {
+		return 0
+	}
+
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 || v > max {
+		d.err = ErrMalformedMessage
+		return 0
+	}
+
+	d.data = d.data[n:]
+	return v
+}
+
+// bytes returns nil for empty fields, so decoding gives back what was
+// encoded for messages built with nil slices.
+func (d *decoder) bytes() []byte {
+	size := d.uvarint(1<<64 - 1)
+	if d.err == nil && size > uint64(len(d.data)) {
+		d.err = ErrMalformedMessage
+	}
+	if d.err != nil || size == 0 {
+		return nil
+	}
+
+	b := append([]byte(nil), d.data[:size]...)
+	d.data = d.data[size:]
+	return b
+}
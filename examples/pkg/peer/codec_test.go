@@ -0,0 +1,162 @@
+package peer
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"flag"
+	Addressing "gemelos/pkg/addressing"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// goldenIdentity is a fixed key, so golden signatures never change.
+func goldenIdentity() *Addressing.NodeIdentity {
+	seed := make([]byte, ed25519.SeedSize)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	identity, _ := Addressing.NewNodeIdentityFromKey(ed25519.NewKeyFromSeed(seed))
+	return identity
+}
+
+func goldenMessages() map[string]*Message {
+	identity := goldenIdentity()
+	sender := identity.Raw("10.10.210.21:4000")
+
+	message := func(messageType MessageType, nonce uint64) *Message {
+		return &Message{
+			Version: ProtocolVersion,
+			Type:    messageType,
+			Nonce:   nonce,
+			TTL:     DefaultTTL,
+			Sender:  sender,
+		}
+	}
+
+	messages := map[string]*Message{
+		"ping":                message(Ping, 1),
+		"pong":                message(Pong, 1),
+		"join":                message(Join, 2),
+		"club_state_request":  message(ClubStateRequest, 3),
+		"club_state_response": message(ClubStateResponse, 3),
+		"route_request":       message(RouteRequest, 4),
+		"route_response":      message(RouteResponse, 4),
+		"forward":             message(Forward, 5),
+		"deliver":             message(Deliver, 5),
+		"deliver_report":      message(Deliver, 5),
+		"leave":               message(Leave, 6),
+		"broadcast":           message(Broadcast, 8),
+	}
+
+	messages["club_state_request"].Club = "Hat"
+	messages["club_state_response"].Club = "Hat"
+	messages["club_state_response"].Peers = []string{"10.10.210.22:4000", "10.10.210.23:4000"}
+	messages["route_request"].Destination = "41.210.42.31:4000"
+	messages["route_response"].Destination = "41.210.42.31:4000"
+	messages["route_response"].Peers = []string{"10.10.210.24:4000"}
+	messages["route_response"].Payload = []byte("BootForward")
+	messages["forward"].Destination = "41.210.42.31:4000"
+	messages["forward"].Payload = []byte("gemini")
+	messages["forward"].Hops = 3
+	messages["forward"].TTL = DefaultTTL - 3
+	messages["deliver"].Destination = "41.210.42.31:4000"
+	messages["deliver"].Payload = []byte("gemini")
+	messages["deliver_report"].Destination = "41.210.42.31:4000"
+	messages["deliver_report"].Payload = []byte("Delivered")
+	messages["deliver_report"].Hops = 3
+
+	// a relayed broadcast wraps the original one, signed by its origin
+	original := message(Broadcast, 7)
+	original.Payload = []byte("gemini")
+	original.Sign(identity)
+	messages["broadcast"].Club = "Hat"
+	messages["broadcast"].Payload = Encode(original)
+
+	for _, m := range messages {
+		m.Sign(identity)
+	}
+
+	return messages
+}
+
+func TestGoldenMessages(t *testing.T) {
+	for name, m := range goldenMessages() {
+		path := filepath.Join("testdata", name+".golden")
+		encoded := Encode(m)
+
+		if *update {
+			if err := ioutil.WriteFile(path, []byte(hex.EncodeToString(encoded)+"\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+
+		golden, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal("Missing golden file, run go test -update", err)
+		}
+
+		expected, err := hex.DecodeString(strings.TrimSpace(string(golden)))
+		if err != nil {
+			t.Fatal("Faulty golden file", path, err)
+		}
+
+		if !bytes.Equal(encoded, expected) {
+			t.Log("Encoding of", name, "does not match its golden file")
+			t.Fail()
+		}
+
+		decoded, err := Decode(expected)
+		if err != nil || !reflect.DeepEqual(decoded, m) {
+			t.Log("Golden", name, "does not decode to the original message", err)
+			t.Fail()
+		}
+
+		if !decoded.Verify() {
+			t.Log("Golden", name, "signature does not verify")
+			t.Fail()
+		}
+
+		if decoded.Type == Broadcast {
+			if original, err := Decode(decoded.Payload); err != nil || original.Type != Broadcast || !original.Verify() {
+				t.Log("Golden", name, "does not wrap a signed broadcast", err)
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestDecodeRejectsMalformed(t *testing.T) {
+	encoded := Encode(goldenMessages()["forward"])
+
+	for i := 0; i < len(encoded); i++ {
+		if _, err := Decode(encoded[:i]); err == nil {
+			t.Log("Truncated message was accepted at", i)
+			t.Fail()
+			break
+		}
+	}
+
+	if _, err := Decode(append(encoded, 0)); err != ErrMalformedMessage {
+		t.Log("Trailing bytes should be refused", err)
+		t.Fail()
+	}
+
+	future := append([]byte{ProtocolVersion + 1}, encoded[1:]...)
+	if _, err := Decode(future); err != ErrUnsupportedVersion {
+		t.Log("Unknown versions should be refused", err)
+		t.Fail()
+	}
+
+	unknown := append([]byte{ProtocolVersion, 0x7f}, encoded[2:]...)
+	if _, err := Decode(unknown); err != ErrMalformedMessage {
+		t.Log("Unknown message types should be refused", err)
+		t.Fail()
+	}
+}
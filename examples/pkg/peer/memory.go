@@ -0,0 +1,156 @@
+package peer
+
+import (
+	"sync"
+)
+
+// DefaultInboxSize is how many envelopes a memory transport buffers before
+// senders get ErrInboxFull.
+const DefaultInboxSize = 1024
+
+type (
+	// MemoryNetwork connects memory transports living in the same process,
+	// so a whole network of nodes can run inside a test or a simulation.
+	MemoryNetwork struct {
+		InboxSize int
+
+		mu        sync.RWMutex
+		listeners map[string]*MemoryTransport
+	}
+
+	// MemoryTransport delivers by handing envelopes over a channel. Sending
+	// never blocks: a peer that does not keep up loses envelopes and the
+	// sender learns about it through ErrInboxFull.
+	MemoryTransport struct {
+		network *MemoryNetwork
+
+		mu     sync.RWMutex
+		addr   string
+		inbox  chan Envelope
+		closed bool
+	}
+)
+
+var _ Transport = (*MemoryTransport)(nil)
+
+func NewMemoryNetwork() *MemoryNetwork {
+	return &MemoryNetwork{
+		InboxSize: DefaultInboxSize,
+		listeners: make(map[string]*MemoryTransport),
+	}
+}
+
+func (n *MemoryNetwork) NewTransport() *MemoryTransport {
+	return &MemoryTransport{
+		network: n,
+		inbox:   make(chan Envelope, n.InboxSize),
+	}
+}
+
+func (n *MemoryNetwork) lookup(addr string) (*MemoryTransport, bool) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	t, ok := n.listeners[addr]
+	return t, ok
+}
+
+func (t *MemoryTransport) Listen(addr string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return ErrClosed
+	}
+	if t.addr != "" {
+		return ErrAlreadyStarted
+	}
+
+	t.network.mu.Lock()
+	defer t.network.mu.Unlock()
+
+	if _, taken := t.network.listeners[addr]; taken {
+		return ErrAddressInUse
+	}
+
+	t.network.listeners[addr] = t
+	t.addr = addr
+
+	return nil
+}
+
+// Dial only checks that someone listens on addr, there is no connection to
+// set up in memory.
+func (t *MemoryTransport) Dial(addr string) error {
+	if _, ok := t.network.lookup(addr); !ok {
+		return ErrUnreachable
+	}
+	return nil
+}
+
+// Send copies the payload, so callers are free to reuse it.
+func (t *MemoryTransport) Send(to string, payload []byte) error {
+	from := t.LocalAddr()
+	if from == "" {
+		return ErrNotListening
+	}
+
+	peer, ok := t.network.lookup(to)
+	if !ok {
+		return ErrUnreachable
+	}
+
+	envelope := Envelope{
+		From:    from,
+		To:      to,
+		Payload: append([]byte(nil), payload...),
+	}
+
+	peer.mu.RLock()
+	defer peer.mu.RUnlock()
+
+	if peer.closed {
+		return ErrUnreachable
+	}
+
+	select {
+	case peer.inbox <- envelope:
+		return nil
+	default:
+		return ErrInboxFull
+	}
+}
+
+func (t *MemoryTransport) Receive() <-chan Envelope {
+	return t.inbox
+}
+
+func (t *MemoryTransport) LocalAddr() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.closed {
+		return ""
+	}
+	return t.addr
+}
+
+func (t *MemoryTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return ErrClosed
+	}
+	t.closed = true
+
+	if t.addr != "" {
+		t.network.mu.Lock()
+		delete(t.network.listeners, t.addr)
+		t.network.mu.Unlock()
+	}
+
+	close(t.inbox)
+
+	return nil
+}
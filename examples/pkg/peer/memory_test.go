@@ -0,0 +1,110 @@
+package peer
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestMemoryTransport(t *testing.T) {
+	network := NewMemoryNetwork()
+	a, b := network.NewTransport(), network.NewTransport()
+
+	if err := a.Send("10.0.0.2:4000", []byte("hello")); err != ErrNotListening {
+		t.Log("Sending before listening should fail", err)
+		t.Fail()
+	}
+
+	a.Listen("10.0.0.1:4000")
+	b.Listen("10.0.0.2:4000")
+
+	if err := network.NewTransport().Listen("10.0.0.1:4000"); err != ErrAddressInUse {
+		t.Log("Two transports should not listen on the same address", err)
+		t.Fail()
+	}
+
+	if err := a.Dial("10.0.0.3:4000"); err != ErrUnreachable {
+		t.Log("Dialing an address nobody listens on should fail", err)
+		t.Fail()
+	}
+
+	payload := []byte("hello")
+	if err := a.Send("10.0.0.2:4000", payload); err != nil {
+		t.Log("Faulty memory send", err)
+		t.Fail()
+	}
+	payload[0] = 'j'
+
+	envelope := <-b.Receive()
+	if envelope.From != "10.0.0.1:4000" || envelope.To != "10.0.0.2:4000" || !bytes.Equal(envelope.Payload, []byte("hello")) {
+		t.Log("Faulty memory delivery", envelope)
+		t.Fail()
+	}
+
+	b.Close()
+
+	if _, open := <-b.Receive(); open {
+		t.Log("Closing a transport should close its inbox")
+		t.Fail()
+	}
+
+	if err := a.Send("10.0.0.2:4000", payload); err != ErrUnreachable {
+		t.Log("A closed transport should be unreachable", err)
+		t.Fail()
+	}
+}
+
+func TestMemoryInboxFull(t *testing.T) {
+	network := NewMemoryNetwork()
+	network.InboxSize = 2
+
+	a, b := network.NewTransport(), network.NewTransport()
+	a.Listen("a")
+	b.Listen("b")
+
+	a.Send("b", nil)
+	a.Send("b", nil)
+
+	if err := a.Send("b", nil); err != ErrInboxFull {
+		t.Log("A full inbox should push back on the sender", err)
+		t.Fail()
+	}
+}
+
+func TestMemoryNetworkRelay(t *testing.T) {
+	const nodes = 2000
+
+	network := NewMemoryNetwork()
+	transports := make([]*MemoryTransport, nodes)
+	for i := range transports {
+		transports[i] = network.NewTransport()
+		transports[i].Listen(fmt.Sprintf("node-%d", i))
+	}
+
+	// every node relays what it receives to the next one, the last one
+	// hands it back to the test
+	done := make(chan Envelope)
+	for i, transport := range transports {
+		go func(i int, transport *MemoryTransport) {
+			for envelope := range transport.Receive() {
+				if i == nodes-1 {
+					done <- envelope
+					continue
+				}
+				transport.Send(fmt.Sprintf("node-%d", i+1), envelope.Payload)
+			}
+		}(i, transport)
+	}
+
+	transports[0].Send("node-1", []byte("relay"))
+
+	envelope := <-done
+	if envelope.From != fmt.Sprintf("node-%d", nodes-2) || string(envelope.Payload) != "relay" {
+		t.Log("Payload got lost relaying through the network", envelope)
+		t.Fail()
+	}
+
+	for _, transport := range transports {
+		transport.Close()
+	}
+}
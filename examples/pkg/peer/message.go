@@ -0,0 +1,135 @@
+package peer
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	Addressing "gemelos/pkg/addressing"
+)
+
+// ProtocolVersion is the version of the wire format Encode writes.
+const ProtocolVersion = 1
+
+// DefaultTTL is how many hops a message may take before it is dropped.
+const DefaultTTL = 32
+
+type MessageType uint8
+
+const (
+	Ping MessageType = iota + 1
+	Pong
+	Join
+	ClubStateRequest
+	ClubStateResponse
+	RouteRequest
+	Forward
+	Deliver
+	Leave
+	RouteResponse
+	Broadcast
+)
+
+// Message is the one message every node exchanges; which fields are used
+// depends on its type:
+//
+//	Ping                      Destination, when set, the peer to probe on
+//	                          behalf of the sender
+//	Join                      Club, when set, the club of the peer the
+//	                          joiner asks to be kept in regardless of its
+//	                          cases
+//	Pong, Leave               no body, Pong echoes the Ping nonce
+//	ClubStateRequest          Club, empty for every club
+//	ClubStateResponse         Club, Peers
+//	RouteRequest              Destination, Peers the answer should avoid
+//	RouteResponse             Destination, Peers holding the next hop if
+//	                          there is one, Payload its RoutingStatus; it
+//	                          echoes the RouteRequest nonce
+//	Forward                   Destination, Payload
+//	Deliver                   Destination, Payload holding the outcome of
+//	                          the Forward with the same nonce
+//	Broadcast                 Payload, relayed wrapped in another Broadcast
+//	                          whose Club is the club it is relayed through
+//	                          and whose Payload is the encoded original
+//
+// Sender is the raw address of the node that created the message, an
+// identity raw address whenever the message is signed. TTL and Hops change
+// on every hop and are left out of the signature, everything else is
+// covered by it.
+type Message struct {
+	Version     uint8
+	Type        MessageType
+	Nonce       uint64
+	TTL         uint32
+	Hops        uint32
+	Sender      string
+	Destination string
+	Club        string
+	Peers       []string
+	Payload     []byte
+	Signature   []byte
+}
+
+var messageTypes = map[MessageType]string{
+	Ping:              "Ping",
+	Pong:              "Pong",
+	Join:              "Join",
+	ClubStateRequest:  "ClubStateRequest",
+	ClubStateResponse: "ClubStateResponse",
+	RouteRequest:      "RouteRequest",
+	Forward:           "Forward",
+	Deliver:           "Deliver",
+	Leave:             "Leave",
+	RouteResponse:     "RouteResponse",
+	Broadcast:         "Broadcast",
+}
+
+func (t MessageType) String() string {
+	if name, ok := messageTypes[t]; ok {
+		return name
+	}
+	return "Unknown"
+}
+
+// NewMessage stamps a message of the current version with a random nonce
+// and the default TTL.
+func NewMessage(messageType MessageType, sender string) *Message {
+	return &Message{
+		Version: ProtocolVersion,
+		Type:    messageType,
+		Nonce:   NewNonce(),
+		TTL:     DefaultTTL,
+		Sender:  sender,
+	}
+}
+
+func NewNonce() uint64 {
+	var nonce [8]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		panic(err)
+	}
+	return binary.BigEndian.Uint64(nonce[:])
+}
+
+// SigningBytes is what the sender signs: the encoding of the message with
+// TTL, Hops and Signature zeroed.
+func (m *Message) SigningBytes() []byte {
+	unsigned := *m
+	unsigned.TTL = 0
+	unsigned.Hops = 0
+	unsigned.Signature = nil
+	return Encode(&unsigned)
+}
+
+func (m *Message) Sign(identity *Addressing.NodeIdentity) {
+	m.Signature = identity.Sign(m.SigningBytes())
+}
+
+// Verify checks the signature against the public key in the sender raw
+// address, so unsigned messages and messages from plain addresses never
+// verify.
+func (m *Message) Verify() bool {
+	pub, ok := Addressing.PublicKeyOf(m.Sender)
+	if !ok {
+		return false
+	}
+	return Addressing.VerifySignature(pub, m.SigningBytes(), m.Signature)
+}
@@ -0,0 +1,46 @@
+package peer
+
+import (
+	Addressing "gemelos/pkg/addressing"
+	"testing"
+)
+
+func TestMessageSignature(t *testing.T) {
+	identity, _ := Addressing.NewNodeIdentity(nil)
+
+	m := NewMessage(Forward, identity.Raw("10.10.210.21:4000"))
+	m.Destination = "41.210.42.31:4000"
+	m.Payload = []byte("gemini")
+	m.Sign(identity)
+
+	if !m.Verify() {
+		t.Log("Faulty message signature")
+		t.Fail()
+	}
+
+	m.TTL--
+	m.Hops++
+
+	if !m.Verify() {
+		t.Log("Forwarding should not invalidate the signature")
+		t.Fail()
+	}
+
+	m.Payload = []byte("gemelos")
+
+	if m.Verify() {
+		t.Log("A tampered payload should not verify")
+		t.Fail()
+	}
+
+	unsigned := NewMessage(Ping, "10.10.210.21:4000")
+	if unsigned.Verify() {
+		t.Log("Messages from plain addresses should never verify")
+		t.Fail()
+	}
+
+	if NewMessage(Ping, "").Nonce == NewMessage(Ping, "").Nonce {
+		t.Log("Every message should get its own nonce")
+		t.Fail()
+	}
+}
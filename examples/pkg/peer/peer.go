@@ -1 +1,35 @@
 package peer
+
+import (
+	"errors"
+)
+
+type (
+	// Envelope is a payload on its way between two transport addresses.
+	Envelope struct {
+		From    string
+		To      string
+		Payload []byte
+	}
+
+	// Transport moves opaque payloads between nodes. A node listens on the
+	// endpoint its raw address advertises, anything it receives shows up on
+	// the Receive channel, which is closed along with the transport.
+	Transport interface {
+		Listen(addr string) error
+		Dial(addr string) error
+		Send(to string, payload []byte) error
+		Receive() <-chan Envelope
+		LocalAddr() string
+		Close() error
+	}
+)
+
+var (
+	ErrClosed         = errors.New("Transport is closed")
+	ErrNotListening   = errors.New("Transport is not listening")
+	ErrAddressInUse   = errors.New("Address already in use")
+	ErrUnreachable    = errors.New("Peer address is unreachable")
+	ErrInboxFull      = errors.New("Peer inbox is full")
+	ErrAlreadyStarted = errors.New("Transport is already listening")
+)
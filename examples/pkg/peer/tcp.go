@@ -0,0 +1,457 @@
+package peer
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// FrameHeaderSize is the length prefix in front of every frame, a big endian
+// uint32.
+const FrameHeaderSize = 4
+
+var (
+	ErrFrameTooLarge = errors.New("Frame exceeds the maximum frame size")
+	ErrSpoofedAddr   = errors.New("Claimed address is not on the host the connection comes from")
+)
+
+type (
+	TCPConfig struct {
+		// DialTimeout bounds connecting to a peer.
+		DialTimeout time.Duration
+		// ReadTimeout closes connections idle for longer than that.
+		ReadTimeout time.Duration
+		// WriteTimeout bounds writing a frame; a peer that stops reading
+		// makes its sender fail after that long.
+		WriteTimeout time.Duration
+		MaxFrameSize int
+		InboxSize    int
+	}
+
+	// TCPTransport keeps at most one pooled connection per peer and carries
+	// length prefixed frames over it both ways. The first frame on every
+	// connection is a hello holding the listen address of the dialer, so the
+	// other side can answer on the same connection. The hello is only
+	// trusted for the host the connection comes from, see handshake.
+	//
+	// When two peers dial each other at once both connections are kept
+	// open and read from, each side writing on the one it pooled first.
+	//
+	// Incoming frames are only read as fast as Receive is drained: once the
+	// inbox is full the readers stop, TCP flow control kicks in and senders
+	// run into their WriteTimeout. Cancelling the context the transport was
+	// created with closes it.
+	TCPTransport struct {
+		config TCPConfig
+		ctx    context.Context
+		cancel context.CancelFunc
+
+		mu       sync.Mutex
+		addr     string
+		listener net.Listener
+		conns    map[string]*tcpConn
+		open     map[net.Conn]bool
+		closed   bool
+
+		readers sync.WaitGroup
+		inbox   chan Envelope
+	}
+
+	tcpConn struct {
+		addr string
+		conn net.Conn
+
+		writeMu sync.Mutex
+	}
+)
+
+var _ Transport = (*TCPTransport)(nil)
+
+func DefaultTCPConfig() TCPConfig {
+	return TCPConfig{
+		DialTimeout:  5 * time.Second,
+		ReadTimeout:  2 * time.Minute,
+		WriteTimeout: 5 * time.Second,
+		MaxFrameSize: 1 << 20,
+		InboxSize:    DefaultInboxSize,
+	}
+}
+
+func NewTCPTransport(ctx context.Context, config TCPConfig) *TCPTransport {
+	ctx, cancel := context.WithCancel(ctx)
+
+	t := &TCPTransport{
+		config: config,
+		ctx:    ctx,
+		cancel: cancel,
+		conns:  make(map[string]*tcpConn),
+		open:   make(map[net.Conn]bool),
+		inbox:  make(chan Envelope, config.InboxSize),
+	}
+
+	go func() {
+		<-ctx.Done()
+		t.Close()
+	}()
+
+	return t
+}
+
+// Listen accepts peers on addr, a port of 0 picks a free one which
+// LocalAddr then reports.
+func (t *TCPTransport) Listen(addr string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return ErrClosed
+	}
+	if t.listener != nil {
+		return ErrAlreadyStarted
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	t.listener = listener
+	t.addr = listener.Addr().String()
+
+	t.readers.Add(1)
+	go t.accept(listener)
+
+	return nil
+}
+
+func (t *TCPTransport) Dial(addr string) error {
+	_, err := t.connection(addr)
+	return err
+}
+
+// Send writes payload as a single frame to the peer listening on to,
+// reusing the pooled connection and dialing once more if it went stale.
+// A write that times out is not retried, the peer is not keeping up.
+func (t *TCPTransport) Send(to string, payload []byte) error {
+	if len(payload) > t.config.MaxFrameSize {
+		return ErrFrameTooLarge
+	}
+
+	for attempt := 0; ; attempt++ {
+		c, err := t.connection(to)
+		if err != nil {
+			return err
+		}
+
+		if err = t.write(c, payload); err == nil {
+			return nil
+		}
+
+		t.drop(c)
+		if netErr, ok := err.(net.Error); attempt > 0 || (ok && netErr.Timeout()) {
+			return err
+		}
+	}
+}
+
+func (t *TCPTransport) Receive() <-chan Envelope {
+	return t.inbox
+}
+
+func (t *TCPTransport) LocalAddr() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return ""
+	}
+	return t.addr
+}
+
+// Close stops listening, closes every connection, pooled or not, still
+// handshaking or not, and, once the readers are done, the Receive channel.
+func (t *TCPTransport) Close() error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return ErrClosed
+	}
+	t.closed = true
+
+	if t.listener != nil {
+		t.listener.Close()
+	}
+	for conn := range t.open {
+		conn.Close()
+	}
+	t.conns = make(map[string]*tcpConn)
+	t.open = make(map[net.Conn]bool)
+	t.mu.Unlock()
+
+	t.cancel()
+	t.readers.Wait()
+	close(t.inbox)
+
+	return nil
+}
+
+// connection returns the pooled connection to addr, dialing it if needed.
+func (t *TCPTransport) connection(addr string) (*tcpConn, error) {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return nil, ErrClosed
+	}
+	if t.listener == nil {
+		t.mu.Unlock()
+		return nil, ErrNotListening
+	}
+	if c, ok := t.conns[addr]; ok {
+		t.mu.Unlock()
+		return c, nil
+	}
+	local := t.addr
+	t.mu.Unlock()
+
+	dialer := net.Dialer{Timeout: t.config.DialTimeout}
+	conn, err := dialer.DialContext(t.ctx, "tcp", addr)
+	if err != nil {
+		return nil, ErrUnreachable
+	}
+	if !t.track(conn) {
+		return nil, ErrClosed
+	}
+
+	c := &tcpConn{addr: addr, conn: conn}
+	if err := t.write(c, []byte(local)); err != nil {
+		t.untrack(conn)
+		return nil, err
+	}
+
+	return t.register(c), nil
+}
+
+// register pools c unless a connection to the same peer beat it there, in
+// which case c is still read from but never written to: the peer may have
+// pooled it on its side.
+func (t *TCPTransport) register(c *tcpConn) *tcpConn {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		c.conn.Close()
+		return c
+	}
+
+	t.readers.Add(1)
+	go t.read(c)
+
+	if pooled, ok := t.conns[c.addr]; ok {
+		return pooled
+	}
+	t.conns[c.addr] = c
+
+	return c
+}
+
+func (t *TCPTransport) drop(c *tcpConn) {
+	t.mu.Lock()
+	if t.conns[c.addr] == c {
+		delete(t.conns, c.addr)
+	}
+	t.mu.Unlock()
+
+	t.untrack(c.conn)
+}
+
+// track records an open connection for Close to close, or closes it at
+// once if the transport already is.
+func (t *TCPTransport) track(conn net.Conn) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		conn.Close()
+		return false
+	}
+	t.open[conn] = true
+	return true
+}
+
+func (t *TCPTransport) untrack(conn net.Conn) {
+	if buffered, ok := conn.(*bufferedConn); ok {
+		conn = buffered.Conn
+	}
+
+	t.mu.Lock()
+	delete(t.open, conn)
+	t.mu.Unlock()
+
+	conn.Close()
+}
+
+func (t *TCPTransport) accept(listener net.Listener) {
+	defer t.readers.Done()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		if t.track(conn) {
+			go t.handshake(conn)
+		}
+	}
+}
+
+// handshake reads the hello frame of an accepted connection to learn where
+// its dialer listens. The claimed address has to be on the host the
+// connection comes from, otherwise any peer could take over the pool slot
+// of another one and receive its traffic; an unspecified host, a dialer
+// listening on every interface, stands for that host.
+func (t *TCPTransport) handshake(conn net.Conn) {
+	if t.config.ReadTimeout > 0 {
+		conn.SetReadDeadline(time.Now().Add(t.config.ReadTimeout))
+	}
+
+	r := bufio.NewReader(conn)
+	hello, err := ReadFrame(r, t.config.MaxFrameSize)
+	if err != nil || len(hello) == 0 {
+		t.untrack(conn)
+		return
+	}
+
+	addr, err := t.claimedAddr(conn, string(hello))
+	if err != nil {
+		t.untrack(conn)
+		return
+	}
+
+	t.register(&tcpConn{addr: addr, conn: &bufferedConn{Conn: conn, r: r}})
+}
+
+// claimedAddr checks the listen address a dialer claims in its hello
+// against the remote host of its connection.
+func (t *TCPTransport) claimedAddr(conn net.Conn, claimed string) (string, error) {
+	remote, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		return "", err
+	}
+	host, port, err := net.SplitHostPort(claimed)
+	if err != nil {
+		return "", err
+	}
+
+	remoteIP := net.ParseIP(remote)
+	if ip := net.ParseIP(host); ip != nil {
+		if ip.IsUnspecified() {
+			return net.JoinHostPort(remote, port), nil
+		}
+		if ip.Equal(remoteIP) {
+			return claimed, nil
+		}
+		return "", ErrSpoofedAddr
+	}
+
+	ctx := t.ctx
+	if t.config.DialTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.config.DialTimeout)
+		defer cancel()
+	}
+
+	ips, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return "", err
+	}
+	for _, ip := range ips {
+		if ip.IP.Equal(remoteIP) {
+			return claimed, nil
+		}
+	}
+	return "", ErrSpoofedAddr
+}
+
+func (t *TCPTransport) read(c *tcpConn) {
+	defer t.readers.Done()
+	defer t.drop(c)
+
+	r := bufio.NewReader(c.conn)
+	local := t.LocalAddr()
+
+	for {
+		if t.config.ReadTimeout > 0 {
+			c.conn.SetReadDeadline(time.Now().Add(t.config.ReadTimeout))
+		}
+
+		payload, err := ReadFrame(r, t.config.MaxFrameSize)
+		if err != nil {
+			return
+		}
+
+		select {
+		case t.inbox <- Envelope{From: c.addr, To: local, Payload: payload}:
+		case <-t.ctx.Done():
+			return
+		}
+	}
+}
+
+func (t *TCPTransport) write(c *tcpConn, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if t.config.WriteTimeout > 0 {
+		c.conn.SetWriteDeadline(time.Now().Add(t.config.WriteTimeout))
+	}
+
+	return WriteFrame(c.conn, payload)
+}
+
+// WriteFrame writes payload behind its length.
+func WriteFrame(w io.Writer, payload []byte) error {
+	frame := make([]byte, FrameHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(frame, uint32(len(payload)))
+	copy(frame[FrameHeaderSize:], payload)
+
+	_, err := w.Write(frame)
+	return err
+}
+
+// ReadFrame reads a frame written by WriteFrame, refusing frames longer
+// than maxSize before allocating them.
+func ReadFrame(r io.Reader, maxSize int) ([]byte, error) {
+	header := make([]byte, FrameHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+
+	size := binary.BigEndian.Uint32(header)
+	if uint64(size) > uint64(maxSize) {
+		return nil, ErrFrameTooLarge
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+// bufferedConn keeps the bytes the handshake already buffered past the
+// hello frame.
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
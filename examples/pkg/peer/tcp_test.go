@@ -0,0 +1,250 @@
+package peer
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func newLoopbackTransport(t *testing.T, ctx context.Context, config TCPConfig) *TCPTransport {
+	transport := NewTCPTransport(ctx, config)
+	if err := transport.Listen("127.0.0.1:0"); err != nil {
+		t.Fatal("Could not listen on loopback", err)
+	}
+	return transport
+}
+
+func receive(t *testing.T, transport Transport) Envelope {
+	select {
+	case envelope := <-transport.Receive():
+		return envelope
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for a frame")
+		return Envelope{}
+	}
+}
+
+func TestFraming(t *testing.T) {
+	var buf bytes.Buffer
+
+	WriteFrame(&buf, []byte("gemini"))
+	WriteFrame(&buf, nil)
+
+	if frame, err := ReadFrame(&buf, 16); err != nil || string(frame) != "gemini" {
+		t.Log("Faulty frame round trip", frame, err)
+		t.Fail()
+	}
+
+	if frame, err := ReadFrame(&buf, 16); err != nil || len(frame) != 0 {
+		t.Log("Faulty empty frame round trip", frame, err)
+		t.Fail()
+	}
+
+	WriteFrame(&buf, make([]byte, 17))
+	if _, err := ReadFrame(&buf, 16); err != ErrFrameTooLarge {
+		t.Log("Frames over the maximum size should be refused", err)
+		t.Fail()
+	}
+}
+
+func TestTCPTransport(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := newLoopbackTransport(t, ctx, DefaultTCPConfig())
+	b := newLoopbackTransport(t, ctx, DefaultTCPConfig())
+
+	if err := a.Send(b.LocalAddr(), []byte("ping")); err != nil {
+		t.Fatal("Faulty tcp send", err)
+	}
+
+	envelope := receive(t, b)
+	if envelope.From != a.LocalAddr() || string(envelope.Payload) != "ping" {
+		t.Log("Faulty tcp delivery", envelope)
+		t.Fail()
+	}
+
+	if err := b.Send(envelope.From, []byte("pong")); err != nil {
+		t.Fatal("Faulty tcp reply", err)
+	}
+
+	if envelope := receive(t, a); envelope.From != b.LocalAddr() || string(envelope.Payload) != "pong" {
+		t.Log("Faulty tcp reply delivery", envelope)
+		t.Fail()
+	}
+
+	a.mu.Lock()
+	b.mu.Lock()
+	pooled := len(a.conns) == 1 && len(b.conns) == 1
+	b.mu.Unlock()
+	a.mu.Unlock()
+
+	if !pooled {
+		t.Log("Both directions should share one pooled connection")
+		t.Fail()
+	}
+
+	if err := a.Send(b.LocalAddr(), make([]byte, DefaultTCPConfig().MaxFrameSize+1)); err != ErrFrameTooLarge {
+		t.Log("Oversized payloads should not be sent", err)
+		t.Fail()
+	}
+}
+
+func TestTCPTransportUnreachable(t *testing.T) {
+	a := newLoopbackTransport(t, context.Background(), DefaultTCPConfig())
+	defer a.Close()
+
+	closed := newLoopbackTransport(t, context.Background(), DefaultTCPConfig())
+	addr := closed.LocalAddr()
+	closed.Close()
+
+	if err := a.Send(addr, []byte("ping")); err != ErrUnreachable {
+		t.Log("Sending to a closed peer should fail", err)
+		t.Fail()
+	}
+}
+
+func TestTCPTransportShutdown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	a := newLoopbackTransport(t, ctx, DefaultTCPConfig())
+	b := newLoopbackTransport(t, context.Background(), DefaultTCPConfig())
+	defer b.Close()
+
+	a.Send(b.LocalAddr(), []byte("ping"))
+	receive(t, b)
+
+	cancel()
+
+	select {
+	case _, open := <-a.Receive():
+		if open {
+			t.Log("Nothing was sent to the cancelled transport")
+			t.Fail()
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Cancelling the context should close the transport")
+	}
+
+	if err := a.Send(b.LocalAddr(), []byte("ping")); err != ErrClosed {
+		t.Log("A closed transport should not send", err)
+		t.Fail()
+	}
+}
+
+func TestTCPTransportBackpressure(t *testing.T) {
+	config := DefaultTCPConfig()
+	config.InboxSize = 1
+	config.WriteTimeout = 200 * time.Millisecond
+
+	a := newLoopbackTransport(t, context.Background(), config)
+	b := newLoopbackTransport(t, context.Background(), config)
+	defer a.Close()
+	defer b.Close()
+
+	payload := make([]byte, config.MaxFrameSize)
+
+	var err error
+	for i := 0; i < 256 && err == nil; i++ {
+		err = a.Send(b.LocalAddr(), payload)
+	}
+
+	if err == nil {
+		t.Log("A peer that does not drain its inbox should push back on the sender")
+		t.Fail()
+	}
+}
+
+func TestTCPTransportSimultaneousDial(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config := DefaultTCPConfig()
+	config.ReadTimeout = 0
+
+	a := newLoopbackTransport(t, ctx, config)
+	b := newLoopbackTransport(t, ctx, config)
+
+	// both sides dial at once, each ends up with a connection it lost the
+	// pool slot with
+	done := make(chan error, 2)
+	go func() { done <- a.Dial(b.LocalAddr()) }()
+	go func() { done <- b.Dial(a.LocalAddr()) }()
+	for i := 0; i < 2; i++ {
+		if err := <-done; err != nil {
+			t.Fatal("Faulty dial", err)
+		}
+	}
+
+	if err := a.Send(b.LocalAddr(), []byte("ping")); err != nil {
+		t.Fatal("Faulty tcp send", err)
+	}
+	if envelope := receive(t, b); string(envelope.Payload) != "ping" || envelope.From != a.LocalAddr() {
+		t.Log("Frames should still flow after a simultaneous dial", envelope)
+		t.Fail()
+	}
+
+	closed := make(chan error, 1)
+	go func() { closed <- a.Close() }()
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close hung while peer b is still up")
+	}
+}
+
+func TestTCPTransportSpoofedHello(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := newLoopbackTransport(t, ctx, DefaultTCPConfig())
+	b := newLoopbackTransport(t, ctx, DefaultTCPConfig())
+
+	conn, err := net.Dial("tcp", b.LocalAddr())
+	if err != nil {
+		t.Fatal("Could not dial", err)
+	}
+	defer conn.Close()
+
+	// claim to be a peer on another host
+	WriteFrame(conn, []byte("10.1.2.3:4000"))
+	WriteFrame(conn, []byte("spoofed"))
+
+	if err := a.Send(b.LocalAddr(), []byte("genuine")); err != nil {
+		t.Fatal("Faulty tcp send", err)
+	}
+	if envelope := receive(t, b); string(envelope.Payload) != "genuine" {
+		t.Log("Frames of a peer claiming another host should be dropped", string(envelope.Payload))
+		t.Fail()
+	}
+
+	if _, err := conn.Read(make([]byte, 1)); err == nil {
+		t.Log("The connection of a peer claiming another host should be closed")
+		t.Fail()
+	}
+
+	c, _ := net.Pipe()
+	defer c.Close()
+	if addr, err := b.claimedAddr(&remoteConn{Conn: c, remote: "192.0.2.7:5555"}, "0.0.0.0:4000"); err != nil || addr != "192.0.2.7:4000" {
+		t.Log("An unspecified host should stand for the remote host", addr, err)
+		t.Fail()
+	}
+	if _, err := b.claimedAddr(&remoteConn{Conn: c, remote: "192.0.2.7:5555"}, "192.0.2.8:4000"); err != ErrSpoofedAddr {
+		t.Log("An address on another host should be refused", err)
+		t.Fail()
+	}
+}
+
+// remoteConn fakes the remote address of a connection.
+type remoteConn struct {
+	net.Conn
+	remote string
+}
+
+func (c *remoteConn) RemoteAddr() net.Addr {
+	addr, _ := net.ResolveTCPAddr("tcp", c.remote)
+	return addr
+}
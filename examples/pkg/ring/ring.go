@@ -1,14 +1,18 @@
 package ring
 
 import (
-	"bytes"
-	"encoding/binary"
 	"math/big"
 )
 
 type (
 	Ring interface {
-		GetDistance([]byte, []byte) uint64
+		GetDistance([]byte, []byte) *big.Int
+		ClockwiseDistance([]byte, []byte) *big.Int
+		CounterClockwiseDistance([]byte, []byte) *big.Int
+		ShortestDistance([]byte, []byte) *big.Int
+		Less([]byte, []byte) bool
+		Between([]byte, []byte, []byte) bool
+		Closer([]byte, []byte, []byte) bool
 	}
 
 	GeminiRing struct {
@@ -27,18 +31,69 @@ func NewGeminiRing(order int) *GeminiRing {
 	}
 }
 
-func (gr *GeminiRing) GetDistance(a, b []byte) uint64 {
-	var distance uint64
-	var distBA, rA, rB big.Int
+var _ Ring = (*GeminiRing)(nil)
+
+// GetDistance is the clockwise distance from a to b, kept at full ID width.
+func (gr *GeminiRing) GetDistance(a, b []byte) *big.Int {
+	return gr.ClockwiseDistance(a, b)
+}
+
+// ClockwiseDistance walks the ring from a towards b through increasing
+// values, wrapping around at the ring order.
+func (gr *GeminiRing) ClockwiseDistance(a, b []byte) *big.Int {
+	var distance, rA, rB big.Int
+
+	(&rA).SetBytes(a)
+	(&rB).SetBytes(b)
+
+	(&distance).Sub(&rB, &rA)
+	(&distance).Mod(&distance, &gr.Ring)
+
+	return &distance
+}
+
+// CounterClockwiseDistance walks the ring from a towards b through
+// decreasing values.
+func (gr *GeminiRing) CounterClockwiseDistance(a, b []byte) *big.Int {
+	return gr.ClockwiseDistance(b, a)
+}
+
+// ShortestDistance is the smaller of the clockwise and counter-clockwise
+// distances between a and b.
+func (gr *GeminiRing) ShortestDistance(a, b []byte) *big.Int {
+	cw := gr.ClockwiseDistance(a, b)
+	ccw := gr.CounterClockwiseDistance(a, b)
+
+	if ccw.Cmp(cw) < 0 {
+		return ccw
+	}
+	return cw
+}
+
+// Less orders two IDs by their position on the ring, starting from zero.
+func (gr *GeminiRing) Less(a, b []byte) bool {
+	var rA, rB big.Int
 
 	(&rA).SetBytes(a)
 	(&rB).SetBytes(b)
 
-	(&distBA).Sub(&rB, &rA)
-	(&distBA).Mod(&distBA, &gr.Ring)
+	return rA.Cmp(&rB) < 0
+}
+
+// Between reports whether x sits on the clockwise arc going from a to b,
+// excluding a and including b. When a equals b the arc is the whole ring.
+func (gr *GeminiRing) Between(x, a, b []byte) bool {
+	ax := gr.ClockwiseDistance(a, x)
+	ab := gr.ClockwiseDistance(a, b)
 
-	buf := bytes.NewReader(distBA.Bytes())
-	binary.Read(buf, binary.BigEndian, &distance)
+	if ab.Sign() == 0 {
+		return true
+	}
+	return ax.Sign() > 0 && ax.Cmp(ab) <= 0
+}
 
-	return distance
+// Closer reports whether a is strictly closer to target than b is, using
+// the shortest distance in either direction.
+func (gr *GeminiRing) Closer(a, b, target []byte) bool {
+	return gr.ShortestDistance(a, target).Cmp(gr.ShortestDistance(b, target)) < 0
 }
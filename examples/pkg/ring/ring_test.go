@@ -3,6 +3,7 @@ package ring
 import (
 	"crypto/sha1"
 	"encoding/binary"
+	"math/big"
 	"testing"
 )
 
@@ -36,8 +37,117 @@ func TestGetDistance(t *testing.T) {
 		hash([]byte("10.0.0.2")),
 	)
 
-	if dist == 0 {
+	if dist.Sign() == 0 {
 		t.Log("Wrong distance calculation")
 		t.Fail()
 	}
+
+	if dist.Cmp(gRing.ClockwiseDistance(hash([]byte("10.0.0.1")), hash([]byte("10.0.0.2")))) != 0 {
+		t.Log("Distance is not measured clockwise")
+		t.Fail()
+	}
+}
+
+func TestClockwiseDistance(t *testing.T) {
+	gRing := NewGeminiRing(160)
+
+	a := hash([]byte("10.0.0.1"))
+	b := hash([]byte("10.0.0.2"))
+
+	var sum big.Int
+	(&sum).Add(gRing.ClockwiseDistance(a, b), gRing.CounterClockwiseDistance(a, b))
+
+	if sum.Cmp(&gRing.Ring) != 0 {
+		t.Log("Clockwise and counter-clockwise distances do not add up to the ring")
+		t.Fail()
+	}
+
+	if gRing.ClockwiseDistance(a, a).Sign() != 0 {
+		t.Log("Distance to self is not zero")
+		t.Fail()
+	}
+
+	if gRing.ClockwiseDistance(a, b).BitLen() <= 64 {
+		t.Log("Full width distance got truncated")
+		t.Fail()
+	}
+}
+
+func TestShortestDistance(t *testing.T) {
+	gRing := NewGeminiRing(8)
+
+	if gRing.ShortestDistance([]byte{250}, []byte{4}).Int64() != 10 {
+		t.Log("Shortest distance does not wrap around the ring")
+		t.Fail()
+	}
+
+	if gRing.ShortestDistance([]byte{4}, []byte{250}).Int64() != 10 {
+		t.Log("Shortest distance is not symmetric")
+		t.Fail()
+	}
+
+	if gRing.ShortestDistance([]byte{4}, []byte{20}).Int64() != 16 {
+		t.Log("Wrong shortest distance calculation")
+		t.Fail()
+	}
+}
+
+func TestLess(t *testing.T) {
+	gRing := NewGeminiRing(16)
+
+	if !gRing.Less([]byte{0x00, 0xff}, []byte{0x01, 0x00}) {
+		t.Log("Wrong ordering of ids")
+		t.Fail()
+	}
+
+	if gRing.Less([]byte{0x01, 0x00}, []byte{0x01, 0x00}) {
+		t.Log("An id should not be less than itself")
+		t.Fail()
+	}
+}
+
+func TestBetween(t *testing.T) {
+	gRing := NewGeminiRing(8)
+
+	if !gRing.Between([]byte{10}, []byte{5}, []byte{20}) {
+		t.Log("Id inside the arc is not between its bounds")
+		t.Fail()
+	}
+
+	if !gRing.Between([]byte{2}, []byte{250}, []byte{5}) {
+		t.Log("Arc does not wrap around the ring")
+		t.Fail()
+	}
+
+	if gRing.Between([]byte{5}, []byte{5}, []byte{20}) || !gRing.Between([]byte{20}, []byte{5}, []byte{20}) {
+		t.Log("Arc bounds should be exclusive on the left and inclusive on the right")
+		t.Fail()
+	}
+
+	if gRing.Between([]byte{30}, []byte{5}, []byte{20}) {
+		t.Log("Id outside the arc is between its bounds")
+		t.Fail()
+	}
+}
+
+func TestCloser(t *testing.T) {
+	gRing := NewGeminiRing(160)
+
+	target := make([]byte, 20)
+	a := make([]byte, 20)
+	b := make([]byte, 20)
+
+	// a and b only differ past the first 8 bytes
+	a[19], b[19] = 1, 2
+	target[0] = 0x80
+
+	if gRing.Closer(a, b, target) == gRing.Closer(b, a, target) {
+		t.Log("Closer cannot tell apart ids that differ in their low bytes")
+		t.Fail()
+	}
+
+	if gRing.Closer(a, a, target) {
+		t.Log("An id should not be closer than itself")
+		t.Fail()
+	}
 }
@@ -1 +1,136 @@
 package seed
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"strings"
+)
+
+type (
+	// Source yields the raw addresses of the nodes a joining node first
+	// talks to, plain endpoints or identity raw addresses.
+	Source interface {
+		Seeds(ctx context.Context) ([]string, error)
+	}
+
+	// Static is a list written into the configuration.
+	Static []string
+
+	// File reads a seed file: one seed per line, blank lines and everything
+	// after a # ignored.
+	File string
+
+	// DNS reads seeds out of the TXT records of a name, several per record
+	// separated by spaces or commas, so a seed list can be updated without
+	// shipping a new configuration.
+	DNS struct {
+		Name     string
+		Resolver Resolver
+	}
+
+	Resolver interface {
+		LookupTXT(ctx context.Context, name string) ([]string, error)
+	}
+)
+
+var ErrNoSeeds = errors.New("No seed found in any source")
+
+func (s Static) Seeds(context.Context) ([]string, error) {
+	return dedupe(s), nil
+}
+
+func (f File) Seeds(context.Context) ([]string, error) {
+	file, err := os.Open(string(f))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return Parse(file)
+}
+
+func (d DNS) Seeds(ctx context.Context) ([]string, error) {
+	resolver := d.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	records, err := resolver.LookupTXT(ctx, d.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	seeds := make([]string, 0, len(records))
+	for _, record := range records {
+		seeds = append(seeds, strings.FieldsFunc(record, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})...)
+	}
+
+	return dedupe(seeds), nil
+}
+
+// Parse reads the seed file format.
+func Parse(r io.Reader) ([]string, error) {
+	seeds := make([]string, 0)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			seeds = append(seeds, line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return dedupe(seeds), nil
+}
+
+// Resolve merges the seeds of every source, in order. Sources that fail
+// are skipped as long as another one yields seeds.
+func Resolve(ctx context.Context, sources ...Source) ([]string, error) {
+	seeds := make([]string, 0)
+	var lastErr error
+
+	for _, source := range sources {
+		found, err := source.Seeds(ctx)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		seeds = append(seeds, found...)
+	}
+
+	if len(seeds) == 0 {
+		if lastErr != nil {
+			return nil, lastErr
+		}
+		return nil, ErrNoSeeds
+	}
+
+	return dedupe(seeds), nil
+}
+
+func dedupe(seeds []string) []string {
+	unique := make([]string, 0, len(seeds))
+	seen := make(map[string]bool, len(seeds))
+
+	for _, s := range seeds {
+		if !seen[s] {
+			seen[s] = true
+			unique = append(unique, s)
+		}
+	}
+
+	return unique
+}
@@ -0,0 +1,70 @@
+package seed
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	seeds, err := Parse(strings.NewReader(`
+# bootstrap nodes
+10.10.210.21:4000
+  10.10.210.22:4000   # second seed
+
+10.10.210.21:4000
+`))
+
+	expected := []string{"10.10.210.21:4000", "10.10.210.22:4000"}
+	if err != nil || !reflect.DeepEqual(seeds, expected) {
+		t.Log("Faulty seed file parsing", seeds, err)
+		t.Fail()
+	}
+}
+
+type fakeResolver map[string][]string
+
+func (r fakeResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	if records, ok := r[name]; ok {
+		return records, nil
+	}
+	return nil, errors.New("No such host")
+}
+
+func TestResolve(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "seeds")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "seeds")
+	ioutil.WriteFile(path, []byte("10.0.0.3:4000\n10.0.0.1:4000\n"), 0644)
+
+	resolver := fakeResolver{"seeds.gemelos.test": {"10.0.0.4:4000, 10.0.0.5:4000", "10.0.0.1:4000"}}
+
+	seeds, err := Resolve(context.Background(),
+		Static{"10.0.0.1:4000", "10.0.0.2:4000"},
+		File(path),
+		DNS{Name: "seeds.gemelos.test", Resolver: resolver},
+		DNS{Name: "missing.gemelos.test", Resolver: resolver},
+	)
+
+	expected := []string{"10.0.0.1:4000", "10.0.0.2:4000", "10.0.0.3:4000", "10.0.0.4:4000", "10.0.0.5:4000"}
+	if err != nil || !reflect.DeepEqual(seeds, expected) {
+		t.Log("Faulty seed resolution", seeds, err)
+		t.Fail()
+	}
+
+	if _, err := Resolve(context.Background(), Static{}); err != ErrNoSeeds {
+		t.Log("No seed at all should be an error", err)
+		t.Fail()
+	}
+
+	if _, err := Resolve(context.Background(), File(filepath.Join(dir, "missing"))); err == nil {
+		t.Log("A failing source without any other seed should be reported")
+		t.Fail()
+	}
+}
@@ -0,0 +1,68 @@
+package sim
+
+import (
+	Addressing "gemelos/pkg/addressing"
+	Gemini "gemelos/pkg/gemini"
+	"math/bits"
+)
+
+// SurveyDigitSums groups the nodes by the digit sum of their id bits,
+// reduced to a single digit, and measures which share of the cases of every
+// club each group holds: a group holding them all points into every club of
+// the network, so a node alone in some club can still reach it through the
+// nodes sharing its digit sum.
+func (n *Network) SurveyDigitSums(report *Report) {
+	all := make(map[Gemini.Club]map[Addressing.Case]bool, len(n.Params.Cases))
+	held := make(map[int]map[Gemini.Club]map[Addressing.Case]bool)
+	sizes := make(map[int]int)
+
+	for _, cd := range n.Params.Cases {
+		all[cd.Name] = make(map[Addressing.Case]bool)
+	}
+
+	for _, node := range n.Nodes {
+		sum := digitSum(node.ID)
+		if _, ok := held[sum]; !ok {
+			held[sum] = make(map[Gemini.Club]map[Addressing.Case]bool, len(n.Params.Cases))
+			for _, cd := range n.Params.Cases {
+				held[sum][cd.Name] = make(map[Addressing.Case]bool)
+			}
+		}
+		sizes[sum]++
+
+		for _, cd := range n.Params.Cases {
+			all[cd.Name][node.Cases[cd.Name]] = true
+			held[sum][cd.Name][node.Cases[cd.Name]] = true
+		}
+	}
+
+	for _, sum := range sortedInts(sizes) {
+		stats := DigitSumStats{
+			Sum:   sum,
+			Nodes: sizes[sum],
+			Cases: make(map[Gemini.Club]float64, len(n.Params.Cases)),
+		}
+		for _, cd := range n.Params.Cases {
+			stats.Cases[cd.Name] = 100 * float64(len(held[sum][cd.Name])) / float64(len(all[cd.Name]))
+		}
+		report.DigitSums = append(report.DigitSums, stats)
+	}
+}
+
+// digitSum adds up the bits of id, then the decimal digits of the sum
+// until a single digit is left.
+func digitSum(id Addressing.Addr) int {
+	sum := 0
+	for _, b := range id.GetHash() {
+		sum += bits.OnesCount8(b)
+	}
+
+	for sum > 9 {
+		reduced := 0
+		for ; sum > 0; sum /= 10 {
+			reduced += sum % 10
+		}
+		sum = reduced
+	}
+	return sum
+}
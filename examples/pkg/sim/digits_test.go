@@ -0,0 +1,49 @@
+package sim
+
+import (
+	Addressing "gemelos/pkg/addressing"
+	Gemini "gemelos/pkg/gemini"
+	"testing"
+)
+
+func TestDigitSum(t *testing.T) {
+	cases := map[string]int{
+		"\x00\x00": 0,
+		"\x01\x00": 1,
+		"\xff\x01": 9,
+		"\xff\x03": 1,
+		"\xff\xff": 7,
+	}
+
+	for hashed, sum := range cases {
+		id := &Addressing.Address{Hashed: []byte(hashed), Status: Addressing.Hashed}
+		if got := digitSum(id); got != sum {
+			t.Log("Faulty digit sum", []byte(hashed), got, sum)
+			t.Fail()
+		}
+	}
+}
+
+func TestSurveyDigitSums(t *testing.T) {
+	network := newSeededNetwork(t, "hatboot", 3, 3)
+	report := network.Survey()
+	network.SurveyDigitSums(report)
+
+	nodes := 0
+	for i, stats := range report.DigitSums {
+		nodes += stats.Nodes
+		if stats.Sum > 9 || (i > 0 && stats.Sum <= report.DigitSums[i-1].Sum) {
+			t.Log("Digit sums should be single digits, in order", stats.Sum)
+			t.Fail()
+		}
+		if stats.Cases[Gemini.Hat] <= 0 || stats.Cases[Gemini.Hat] > 100 {
+			t.Log("A group should hold a share of the hat cases", stats.Cases)
+			t.Fail()
+		}
+	}
+
+	if nodes != 500 {
+		t.Log("Every node should have a digit sum", nodes)
+		t.Fail()
+	}
+}
@@ -0,0 +1,149 @@
+package sim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	Gemini "gemelos/pkg/gemini"
+	Peer "gemelos/pkg/peer"
+	"sync"
+	"time"
+)
+
+// settleInterval is how long a broadcast has to reach nobody new to count
+// as settled.
+const settleInterval = 200 * time.Millisecond
+
+// deliveryTimeout is how long a delivery waits for its outcome, which is
+// lost if some node on the way has its inbox full.
+const deliveryTimeout = 5 * time.Second
+
+var ErrNoGeminus = errors.New("The network has no Geminus nodes")
+
+// Connect puts every Geminus on one memory network, listening on its raw
+// address, so deliveries and broadcasts exchange real messages.
+func (n *Network) Connect() error {
+	if !n.Config.Geminus {
+		return ErrNoGeminus
+	}
+
+	network := Peer.NewMemoryNetwork()
+	for _, node := range n.Nodes {
+		transport := network.NewTransport()
+		if err := transport.Listen(node.Geminus.Addr.GetRaw()); err != nil {
+			return err
+		}
+		node.Geminus.Transport = transport
+	}
+	return nil
+}
+
+// serve runs Serve on every Geminus until stop is called, which returns
+// once they all returned, so the nodes can be changed in between.
+func (n *Network) serve() (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var wg sync.WaitGroup
+	for _, node := range n.Nodes {
+		wg.Add(1)
+		go func(node *Node) {
+			defer wg.Done()
+			node.Geminus.Serve(ctx)
+		}(node)
+	}
+
+	return func() {
+		cancel()
+		wg.Wait()
+	}
+}
+
+// SimulateDeliveries delivers an empty payload from one random node to
+// Config.Routes random destinations with Geminus.Deliver, every hop being a
+// message over the memory network, and records the hops each took or why
+// it failed. The network has to be connected.
+func (n *Network) SimulateDeliveries(report *Report) {
+	if len(n.Nodes) < 2 {
+		return
+	}
+
+	stop := n.serve()
+	defer stop()
+
+	source := n.PickRandom()
+	for len(report.Deliveries) < n.Config.Routes {
+		destination := n.PickRandom()
+		if destination == source {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), deliveryTimeout)
+		ack, err := source.Geminus.Deliver(ctx, destination.ID.GetRaw(), nil)
+		cancel()
+
+		delivery := Delivery{Source: source, Destination: destination, Err: err}
+		if ack != nil {
+			delivery.Hops = ack.Hops
+			delivery.Delivered = true
+		} else if failure, ok := err.(*Gemini.DeliveryError); ok {
+			delivery.Hops = failure.Hops
+		}
+		report.Deliveries = append(report.Deliveries, delivery)
+	}
+}
+
+// SimulateBroadcasts broadcasts from one random node once per redundancy in
+// Config.Redundancies, and measures which share of the network each
+// broadcast reached and how long it took to get there. The network has to
+// be connected.
+func (n *Network) SimulateBroadcasts(report *Report) {
+	if len(n.Nodes) < 2 {
+		return
+	}
+
+	var mu sync.Mutex
+	received := make(map[string]int)
+	var last time.Time
+
+	for _, node := range n.Nodes {
+		node.Geminus.OnBroadcast = func(_ string, payload []byte) {
+			mu.Lock()
+			received[string(payload)]++
+			last = time.Now()
+			mu.Unlock()
+		}
+	}
+
+	origin := n.PickRandom()
+	redundancy := n.Params.BroadcastRedundancy
+	defer func() { n.Params.BroadcastRedundancy = redundancy }()
+
+	for _, r := range n.Config.Redundancies {
+		n.Params.BroadcastRedundancy = r
+		stop := n.serve()
+
+		round := fmt.Sprint("redundancy-", r)
+		start := time.Now()
+		origin.Geminus.Broadcast([]byte(round))
+
+		// settled once nobody new got it for a while
+		for count := -1; ; {
+			time.Sleep(settleInterval)
+			mu.Lock()
+			settled := received[round] == count
+			count = received[round]
+			mu.Unlock()
+			if settled {
+				break
+			}
+		}
+		stop()
+
+		stats := BroadcastStats{Redundancy: r, Reached: received[round]}
+		stats.Coverage = 100 * float64(stats.Reached) / float64(len(n.Nodes)-1)
+		if stats.Reached > 0 {
+			stats.TimeToCoverage = last.Sub(start)
+		}
+		report.Broadcasts = append(report.Broadcasts, stats)
+	}
+}
@@ -0,0 +1,80 @@
+package sim
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSimulateBroadcasts(t *testing.T) {
+	layout, _ := NewLayout("default", GeminusAddrLength, 3, 3)
+	report, err := Simulate(Config{Nodes: 300, Layout: layout, Routes: 10, Geminus: true, Redundancies: []int{0, 1}})
+	if err != nil {
+		t.Fatal("Faulty simulation", err)
+	}
+
+	if len(report.Broadcasts) != 2 {
+		t.Fatal("Every redundancy should be broadcast once", len(report.Broadcasts))
+	}
+
+	all := report.Broadcasts[0]
+	if all.Redundancy != 0 || all.Reached != 299 || all.Coverage != 100 || all.TimeToCoverage <= 0 {
+		t.Log("Relaying to every member should cover the network", all)
+		t.Fail()
+	}
+
+	if one := report.Broadcasts[1]; one.Reached == 0 || one.Reached > all.Reached {
+		t.Log("Relaying to one member per club should reach some of the network", one)
+		t.Fail()
+	}
+
+	var out bytes.Buffer
+	report.Print(&out)
+	if !strings.Contains(out.String(), "*---> Broadcast Network Coverage (%): 100.00") {
+		t.Log("The printed report should hold the broadcast coverage", out.String())
+		t.Fail()
+	}
+}
+
+func TestConnect(t *testing.T) {
+	network := newSeededNetwork(t, "hatboot", 3, 3)
+	if err := network.Connect(); err != ErrNoGeminus {
+		t.Log("Only Geminus nodes should be connected", err)
+		t.Fail()
+	}
+}
+
+func TestSimulateDeliveries(t *testing.T) {
+	layout, _ := NewLayout("default", GeminusAddrLength, 3, 3)
+	network, err := NewNetwork(Config{Nodes: 300, Layout: layout, Routes: 50, Geminus: true})
+	if err != nil {
+		t.Fatal("Faulty network", err)
+	}
+	if err := network.Populate(); err != nil {
+		t.Fatal("Faulty population", err)
+	}
+	network.Seed()
+	if err := network.Connect(); err != nil {
+		t.Fatal("Faulty connection", err)
+	}
+
+	report := network.Survey()
+	network.SimulateDeliveries(report)
+
+	if len(report.Deliveries) != 50 {
+		t.Fatal("Every delivery should be recorded", len(report.Deliveries))
+	}
+	for _, delivery := range report.Deliveries {
+		if !delivery.Delivered || delivery.Err != nil || delivery.Hops < 1 || delivery.Hops > 3 {
+			t.Log("A dense network should deliver within a few hops", delivery.Hops, delivery.Err)
+			t.Fail()
+		}
+	}
+
+	var out bytes.Buffer
+	report.Print(&out)
+	if !strings.Contains(out.String(), "50 Payloads delivered through Gemini.Geminus.Deliver") {
+		t.Log("The printed report should hold the delivery stats", out.String())
+		t.Fail()
+	}
+}
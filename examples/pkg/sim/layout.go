@@ -0,0 +1,69 @@
+package sim
+
+import (
+	"errors"
+	"fmt"
+	Gemini "gemelos/pkg/gemini"
+	"sort"
+)
+
+// Layout is a design to simulate: which cases make up the clubs of a node
+// and the strategy routing over them.
+type Layout struct {
+	Name     string
+	Cases    []Gemini.CaseDefinition
+	Strategy Gemini.RoutingStrategy
+}
+
+// layouts builds every known layout out of its case lengths, in the order
+// the layout lists its cases.
+var layouts = map[string]struct {
+	lengths int
+	build   func(addrLength int, lengths []int) Layout
+}{
+	"hatboot": {2, func(_ int, l []int) Layout {
+		return Layout{Cases: Gemini.DefaultCases(l[0], l[1]), Strategy: Gemini.HatBootStrategy{}}
+	}},
+	"default": {2, func(_ int, l []int) Layout {
+		return Layout{Cases: Gemini.DefaultCases(l[0], l[1]), Strategy: Gemini.DefaultStrategy{}}
+	}},
+	"headbodytail": {3, func(addrLength int, l []int) Layout {
+		return Layout{Cases: Gemini.HeadBodyTailCases(addrLength, l[0], l[1], l[2]), Strategy: Gemini.HeadBodyTailStrategy{}}
+	}},
+	"headtailreversed": {2, func(_ int, l []int) Layout {
+		return Layout{Cases: Gemini.HeadTailReversedCases(l[0], l[1]), Strategy: Gemini.HeadTailReversedStrategy{}}
+	}},
+}
+
+var ErrUnknownLayout = errors.New("Unknown layout")
+
+// NewLayout builds a layout by name:
+//
+//	hatboot           Hat and Boot, routed like the two dimensional simulations
+//	default           Hat and Boot, routed with Gemini.DefaultStrategy
+//	headbodytail      Head, Body and Tail, the three dimensional simulation
+//	headtailreversed  Head, Tail and their reversed clubs, the four
+//	                  dimensional simulation
+func NewLayout(name string, addrLength int, lengths ...int) (Layout, error) {
+	l, ok := layouts[name]
+	if !ok {
+		return Layout{}, ErrUnknownLayout
+	}
+	if len(lengths) != l.lengths {
+		return Layout{}, fmt.Errorf("Layout %s needs %d case lengths, got %d", name, l.lengths, len(lengths))
+	}
+
+	layout := l.build(addrLength, lengths)
+	layout.Name = name
+	return layout, nil
+}
+
+// LayoutNames lists the layouts NewLayout knows, sorted.
+func LayoutNames() []string {
+	names := make([]string, 0, len(layouts))
+	for name := range layouts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
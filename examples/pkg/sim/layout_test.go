@@ -0,0 +1,30 @@
+package sim
+
+import (
+	"testing"
+)
+
+func TestNewLayout(t *testing.T) {
+	for _, name := range LayoutNames() {
+		lengths := []int{4, 4}
+		if name == "headbodytail" {
+			lengths = []int{4, 4, 4}
+		}
+
+		layout, err := NewLayout(name, DefaultAddrLength, lengths...)
+		if err != nil || layout.Name != name || layout.Strategy == nil || len(layout.Cases) == 0 {
+			t.Log("Every listed layout should build", name, err)
+			t.Fail()
+		}
+	}
+
+	if _, err := NewLayout("spiral", DefaultAddrLength, 4, 4); err != ErrUnknownLayout {
+		t.Log("An unknown layout should be refused", err)
+		t.Fail()
+	}
+
+	if _, err := NewLayout("headbodytail", DefaultAddrLength, 4, 4); err == nil {
+		t.Log("A layout should refuse the wrong number of case lengths")
+		t.Fail()
+	}
+}
@@ -0,0 +1,377 @@
+package sim
+
+import (
+	"fmt"
+	Addressing "gemelos/pkg/addressing"
+	Gemini "gemelos/pkg/gemini"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+type (
+	// ClubStats surveys the clubs of one case across the network, next to
+	// what the sizing model of GeminiConfig expects.
+	ClubStats struct {
+		Club                  Gemini.Club
+		Count                 int
+		ExpectedCount         float64
+		AverageSize           float64
+		MinSize               int
+		MaxSize               int
+		Covered               int
+		Coverage              float64
+		LonelyIslands         int
+		ExpectedLonelyIslands float64
+	}
+
+	// BroadcastStats is how far a broadcast relayed to Redundancy members
+	// per club spread, and how long it took. Coverage leaves the origin out.
+	BroadcastStats struct {
+		Redundancy     int
+		Reached        int
+		Coverage       float64
+		TimeToCoverage time.Duration
+	}
+
+	// RingStats places the clubs of one case on the ring of ids. A case of
+	// leading bits cuts the ring into Intervals intervals of Interval
+	// percent each; AverageSpan and MaxSpan are the shortest arcs holding
+	// all members of a club, in percent of the ring too.
+	RingStats struct {
+		Club        Gemini.Club
+		Intervals   float64
+		Interval    float64
+		AverageSpan float64
+		MaxSpan     float64
+	}
+
+	// DigitSumStats is the group of nodes whose id bits add up to Sum once
+	// reduced to a single digit, with the percentage of the cases of every
+	// club its nodes hold.
+	DigitSumStats struct {
+		Sum   int
+		Nodes int
+		Cases map[Gemini.Club]float64
+	}
+
+	// Route is one routing attempt, with the decision taken at every hop.
+	Route struct {
+		Source      *Node
+		Destination *Node
+		Hops        int
+		Routed      bool
+		Statuses    []Gemini.RoutingStatus
+	}
+
+	// Delivery is one payload sent with Geminus.Deliver: the hops it took,
+	// or the hops it made before Err stopped it.
+	Delivery struct {
+		Source      *Node
+		Destination *Node
+		Hops        int
+		Delivered   bool
+		Err         error
+	}
+
+	// Report is the outcome of a simulation. LonelyIslands counts the nodes
+	// alone in some of their cases, keyed by those cases joined with "+",
+	// e.g. "Boot" or "Hat+Boot". Deliveries and broadcasts are only
+	// measured over Geminus nodes.
+	Report struct {
+		Layout        string
+		Geminus       bool
+		Nodes         int
+		Clubs         []ClubStats
+		LonelyIslands map[string]int
+		Rings         []RingStats
+		DigitSums     []DigitSumStats
+		Broadcasts    []BroadcastStats
+		Routes        []Route
+		Deliveries    []Delivery
+	}
+)
+
+// Simulate populates and seeds a network for config, then surveys it and
+// routes through it. Geminus nodes are then connected, and deliver and
+// broadcast too.
+func Simulate(config Config) (*Report, error) {
+	network, err := NewNetwork(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := network.Populate(); err != nil {
+		return nil, err
+	}
+	network.Seed()
+
+	report := network.Survey()
+	network.SurveyRing(report)
+	network.SurveyDigitSums(report)
+	network.SimulateRouting(report)
+
+	if config.Geminus {
+		if err := network.Connect(); err != nil {
+			return nil, err
+		}
+		network.SimulateDeliveries(report)
+		network.SimulateBroadcasts(report)
+	}
+	return report, nil
+}
+
+// Survey measures the clubs of every case and the lonely islands.
+func (n *Network) Survey() *Report {
+	report := &Report{
+		Layout:        n.Config.Layout.Name,
+		Geminus:       n.Config.Geminus,
+		Nodes:         len(n.Nodes),
+		Clubs:         make([]ClubStats, 0, len(n.Params.Cases)),
+		LonelyIslands: make(map[string]int),
+	}
+
+	for _, cd := range n.Params.Cases {
+		stats := ClubStats{
+			Club:                  cd.Name,
+			Count:                 len(n.Maps[cd.Name]),
+			ExpectedCount:         n.Params.ExpectedClubCount(cd.Name),
+			ExpectedLonelyIslands: n.Params.ExpectedLonelyIslands(cd.Name),
+		}
+
+		total := 0
+		for _, group := range n.Maps[cd.Name] {
+			size := len(group)
+			total += size
+			if stats.MinSize == 0 || size < stats.MinSize {
+				stats.MinSize = size
+			}
+			if size > stats.MaxSize {
+				stats.MaxSize = size
+			}
+		}
+		if stats.Count > 0 {
+			stats.AverageSize = float64(total) / float64(stats.Count)
+		}
+
+		for _, node := range n.Nodes {
+			if len(node.State.Clubs[cd.Name]) == 0 {
+				stats.LonelyIslands++
+			} else {
+				stats.Covered++
+			}
+		}
+		if len(n.Nodes) > 0 {
+			stats.Coverage = 100 * float64(stats.Covered) / float64(len(n.Nodes))
+		}
+
+		report.Clubs = append(report.Clubs, stats)
+	}
+
+	for _, node := range n.Nodes {
+		alone := make([]string, 0)
+		for _, cd := range n.Params.Cases {
+			if len(node.State.Clubs[cd.Name]) == 0 {
+				alone = append(alone, string(cd.Name))
+			}
+		}
+		if len(alone) > 0 {
+			report.LonelyIslands[strings.Join(alone, "+")]++
+		}
+	}
+
+	return report
+}
+
+// SimulateRouting routes from one random node to Config.Routes random
+// destinations.
+func (n *Network) SimulateRouting(report *Report) {
+	if len(n.Nodes) < 2 {
+		return
+	}
+
+	source := n.PickRandom()
+	for len(report.Routes) < n.Config.Routes {
+		destination := n.PickRandom()
+		if destination != source {
+			report.Routes = append(report.Routes, n.Route(source, destination))
+		}
+	}
+}
+
+// Route hops from source towards destination, every node deciding the next
+// hop out of its own clubs alone, until the destination is reached, no next
+// hop is found or MaxHops is exceeded.
+func (n *Network) Route(source, destination *Node) Route {
+	route := Route{Source: source, Destination: destination}
+
+	for current := source; route.Hops < n.Config.MaxHops; {
+		next, status := n.next(current, destination)
+		route.Statuses = append(route.Statuses, status)
+		if next == nil {
+			break
+		}
+
+		node, ok := n.Lookup(next)
+		if !ok {
+			break
+		}
+
+		route.Hops++
+		if node == destination {
+			route.Routed = true
+			break
+		}
+		current = node
+	}
+
+	return route
+}
+
+// next asks the Geminus of the node for the next hop, or the layout
+// strategy for nodes without one.
+func (n *Network) next(current, destination *Node) (Addressing.Addr, Gemini.RoutingStatus) {
+	if current.Geminus != nil {
+		return current.Geminus.Route(destination.ID.GetRaw())
+	}
+	return n.Config.Layout.Strategy.Next(current.State, destination.ID)
+}
+
+// AverageLonelyIslands averages the lonely islands of several runs.
+func AverageLonelyIslands(reports []*Report) map[string]float64 {
+	average := make(map[string]float64)
+	for _, report := range reports {
+		for kind, count := range report.LonelyIslands {
+			average[kind] += float64(count) / float64(len(reports))
+		}
+	}
+	return average
+}
+
+// Print writes the report the way the original simulations did.
+func (r *Report) Print(w io.Writer) {
+	fmt.Fprintln(w, "Stats:", r.Nodes, "nodes,", r.Layout, "layout")
+	if r.Geminus {
+		fmt.Fprintln(w, "Routed by Gemini.Geminus")
+	}
+	for _, stats := range r.Clubs {
+		fmt.Fprintf(w, "*) %s clubs:\n", stats.Club)
+		fmt.Fprintf(w, "*---> Count: %d (expected %.1f)\n", stats.Count, stats.ExpectedCount)
+		fmt.Fprintf(w, "*---> Average (Actual) Clubs Size: %.2f, from %d to %d\n", stats.AverageSize, stats.MinSize, stats.MaxSize)
+		fmt.Fprintf(w, "*---> %s Clubs Network Coverage (%%): %.2f\n", stats.Club, stats.Coverage)
+		fmt.Fprintf(w, "*---> Number of Nodes Covered by %s Clubs: %d\n", stats.Club, stats.Covered)
+		fmt.Fprintf(w, "*---> Lonely Islands: %d (expected %.1f)\n", stats.LonelyIslands, stats.ExpectedLonelyIslands)
+		fmt.Fprintln(w)
+	}
+
+	if len(r.LonelyIslands) > 0 {
+		fmt.Fprintln(w, "*) Lonely islands:")
+		for _, kind := range sortedKeys(r.LonelyIslands) {
+			fmt.Fprintf(w, "*---> Alone in %s: %d\n", kind, r.LonelyIslands[kind])
+		}
+		fmt.Fprintln(w)
+	}
+
+	for _, stats := range r.Rings {
+		fmt.Fprintf(w, "*) %s clubs on the ring:\n", stats.Club)
+		fmt.Fprintf(w, "*---> The ring holds %.0f intervals of %.4g%% each\n", stats.Intervals, stats.Interval)
+		fmt.Fprintf(w, "*---> Average Club Span (%% of the ring): %.4g, at most %.4g\n", stats.AverageSpan, stats.MaxSpan)
+		fmt.Fprintln(w)
+	}
+
+	if len(r.DigitSums) > 0 {
+		fmt.Fprintln(w, "*) Digit sum distribution:")
+		for _, stats := range r.DigitSums {
+			held := make([]string, 0, len(r.Clubs))
+			for _, club := range r.Clubs {
+				held = append(held, fmt.Sprintf("%.2f%% of %s cases", stats.Cases[club.Club], club.Club))
+			}
+			fmt.Fprintf(w, "*---> [ %d ] = %d nodes, holding %s\n", stats.Sum, stats.Nodes, strings.Join(held, ", "))
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(r.Broadcasts) > 0 {
+		fmt.Fprintln(w, "*) Broadcasts:")
+		for _, stats := range r.Broadcasts {
+			fmt.Fprintln(w, "*---> Redundancy:", stats.Redundancy)
+			fmt.Fprintf(w, "*---> Broadcast Network Coverage (%%): %.2f\n", stats.Coverage)
+			fmt.Fprintln(w, "*---> Number of Nodes Reached by the Broadcast:", stats.Reached)
+			fmt.Fprintln(w, "*---> Time to Coverage:", stats.TimeToCoverage)
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintln(w, "Routing stats:")
+	fmt.Fprintln(w, len(r.Routes), "Requests for routing performed")
+
+	hops := make(map[int]int)
+	failed := make(map[int]int)
+	statuses := make(map[string]int)
+	for _, route := range r.Routes {
+		if route.Routed {
+			hops[route.Hops]++
+		} else {
+			failed[route.Hops]++
+		}
+		for _, status := range route.Statuses {
+			statuses[string(status)]++
+		}
+	}
+
+	for _, k := range sortedInts(hops) {
+		fmt.Fprintln(w, hops[k], "routes happened in", k, "hops")
+	}
+	for _, k := range sortedInts(failed) {
+		fmt.Fprintln(w, failed[k], "routes did not route after", k, "hops")
+	}
+	for _, status := range sortedKeys(statuses) {
+		fmt.Fprintln(w, statuses[status], "hops decided by", status)
+	}
+
+	if len(r.Deliveries) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Delivery stats:")
+	fmt.Fprintln(w, len(r.Deliveries), "Payloads delivered through Gemini.Geminus.Deliver")
+
+	delivered := make(map[int]int)
+	failures := make(map[string]int)
+	for _, delivery := range r.Deliveries {
+		if delivery.Delivered {
+			delivered[delivery.Hops]++
+		} else if failure, ok := delivery.Err.(*Gemini.DeliveryError); ok {
+			failures[string(failure.Reason)]++
+		} else {
+			failures[delivery.Err.Error()]++
+		}
+	}
+
+	for _, k := range sortedInts(delivered) {
+		fmt.Fprintln(w, delivered[k], "have been delivered in", k, "hops")
+	}
+	for _, reason := range sortedKeys(failures) {
+		fmt.Fprintf(w, "%d failed to be delivered (%s)\n", failures[reason], reason)
+	}
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedInts(m map[int]int) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
@@ -0,0 +1,75 @@
+package sim
+
+import (
+	"bytes"
+	Tools "gemelos/pkg/tools"
+	"strings"
+	"testing"
+)
+
+func TestSimulate(t *testing.T) {
+	layout, _ := NewLayout("hatboot", DefaultAddrLength, 8, 2)
+	report, err := Simulate(Config{Nodes: 300, Layout: layout, Routes: 100})
+	if err != nil {
+		t.Fatal("Faulty simulation", err)
+	}
+
+	if len(report.Routes) != 100 || len(report.Clubs) != 2 {
+		t.Log("The report should hold every route and every club", len(report.Routes), len(report.Clubs))
+		t.Fail()
+	}
+
+	// 300 nodes over 256 hat cases leave lonely islands
+	hat := report.Clubs[0]
+	if hat.LonelyIslands == 0 || hat.Covered+hat.LonelyIslands != 300 || report.LonelyIslands["Hat"] != hat.LonelyIslands {
+		t.Log("Nodes alone in their hat should be counted", hat.LonelyIslands, report.LonelyIslands)
+		t.Fail()
+	}
+
+	for _, stats := range report.Clubs {
+		if stats.AverageSize*float64(stats.Count) < 299.9 || stats.AverageSize*float64(stats.Count) > 300.1 {
+			t.Log("The clubs of a case should hold every node once", stats.Club)
+			t.Fail()
+		}
+	}
+
+	var out bytes.Buffer
+	report.Print(&out)
+	if !strings.Contains(out.String(), "100 Requests for routing performed") || !strings.Contains(out.String(), "*) Hat clubs:") {
+		t.Log("The printed report should hold the routing and club stats", out.String())
+		t.Fail()
+	}
+
+	average := AverageLonelyIslands([]*Report{report, report})
+	if average["Hat"] != float64(hat.LonelyIslands) {
+		t.Log("Averaging a report with itself should change nothing", average)
+		t.Fail()
+	}
+}
+
+func TestSimulateReplays(t *testing.T) {
+	layout, _ := NewLayout("default", DefaultAddrLength, 10, 8)
+
+	simulate := func(seed int64) *Report {
+		report, err := Simulate(Config{Nodes: 300, Layout: layout, Routes: 50, MaxHops: 20, Source: Tools.NewSource(seed)})
+		if err != nil {
+			t.Fatal("Faulty simulation", err)
+		}
+		return report
+	}
+
+	first, again := simulate(7), simulate(7)
+	for i := range first.Routes {
+		a, b := first.Routes[i], again.Routes[i]
+		if a.Hops != b.Hops || a.Routed != b.Routed || !bytes.Equal(a.Destination.ID.GetHash(), b.Destination.ID.GetHash()) || len(a.Statuses) != len(b.Statuses) {
+			t.Log("A simulation should replay from its seed", i)
+			t.Fail()
+			break
+		}
+	}
+
+	if other := simulate(8); bytes.Equal(other.Routes[0].Source.ID.GetHash(), first.Routes[0].Source.ID.GetHash()) {
+		t.Log("Other seeds should simulate other networks")
+		t.Fail()
+	}
+}
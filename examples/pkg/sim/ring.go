@@ -0,0 +1,58 @@
+package sim
+
+import (
+	Ring "gemelos/pkg/ring"
+	"math"
+	"math/big"
+	"sort"
+)
+
+// SurveyRing places the clubs of every case on the ring of ids, the way
+// the ring interval explorations did: a case made of the leading bits cuts
+// the ring into intervals and its clubs stay inside one, while the others
+// spread their clubs over the whole ring.
+func (n *Network) SurveyRing(report *Report) {
+	ring := Ring.NewGeminiRing(n.Config.AddrLength)
+
+	for _, cd := range n.Params.Cases {
+		stats := RingStats{Club: cd.Name, Intervals: math.Ldexp(1, cd.Length)}
+		stats.Interval = 100 / stats.Intervals
+
+		for _, group := range n.Maps[cd.Name] {
+			span := 100 * clubSpan(ring, group)
+			stats.AverageSpan += span / float64(len(n.Maps[cd.Name]))
+			if span > stats.MaxSpan {
+				stats.MaxSpan = span
+			}
+		}
+
+		report.Rings = append(report.Rings, stats)
+	}
+}
+
+// clubSpan is the shortest arc of the ring holding every node of a club,
+// the ring less the widest gap between two neighbouring members, as a
+// share of the ring.
+func clubSpan(ring *Ring.GeminiRing, club []*Node) float64 {
+	if len(club) < 2 {
+		return 0
+	}
+
+	ids := make([][]byte, 0, len(club))
+	for _, node := range club {
+		ids = append(ids, node.ID.GetHash())
+	}
+	sort.Slice(ids, func(i, j int) bool { return ring.Less(ids[i], ids[j]) })
+
+	widest := ring.ClockwiseDistance(ids[len(ids)-1], ids[0])
+	for i := 1; i < len(ids); i++ {
+		if gap := ring.ClockwiseDistance(ids[i-1], ids[i]); gap.Cmp(widest) > 0 {
+			widest = gap
+		}
+	}
+
+	var span big.Int
+	(&span).Sub(&ring.Ring, widest)
+	share, _ := new(big.Rat).SetFrac(&span, &ring.Ring).Float64()
+	return share
+}
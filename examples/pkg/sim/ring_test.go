@@ -0,0 +1,32 @@
+package sim
+
+import (
+	Gemini "gemelos/pkg/gemini"
+	"testing"
+)
+
+func TestSurveyRing(t *testing.T) {
+	network := newSeededNetwork(t, "hatboot", 4, 4)
+	report := network.Survey()
+	network.SurveyRing(report)
+
+	if len(report.Rings) != 2 {
+		t.Fatal("Every case should be placed on the ring", len(report.Rings))
+	}
+
+	hat, boot := report.Rings[0], report.Rings[1]
+	if hat.Club != Gemini.Hat || hat.Intervals != 16 || hat.Interval != 6.25 {
+		t.Log("A 4 bit case should cut the ring in 16 intervals", hat)
+		t.Fail()
+	}
+
+	if hat.MaxSpan > hat.Interval || hat.AverageSpan <= 0 {
+		t.Log("Hat clubs should stay inside their interval", hat)
+		t.Fail()
+	}
+
+	if boot.AverageSpan < 50 {
+		t.Log("Boot clubs should spread over the ring", boot)
+		t.Fail()
+	}
+}
@@ -0,0 +1,219 @@
+package sim
+
+import (
+	"errors"
+	"fmt"
+	Addressing "gemelos/pkg/addressing"
+	Gemini "gemelos/pkg/gemini"
+	Tools "gemelos/pkg/tools"
+	"math/rand"
+	"time"
+)
+
+const (
+	DefaultAddrLength = 128
+	DefaultRoutes     = 2000
+	DefaultMaxHops    = 200
+)
+
+// DefaultRedundancies are the broadcast redundancies a Geminus simulation
+// measures the coverage of.
+var DefaultRedundancies = []int{2, 4, 8}
+
+// GeminusAddrLength is the id length of Geminus nodes, the digest size of
+// the hasher they derive their ids with.
+var GeminusAddrLength = Addressing.DefaultHasher.Size() * 8
+
+type (
+	// Config describes one simulation: how many nodes with ids of how many
+	// bits, laid out how, and how many routes to try.
+	//
+	// With Geminus set every node is a real Gemini.Geminus holding its own
+	// clubs only, and hops are decided by Geminus.Route, so the simulation
+	// exercises the routing the library ships rather than the bare layout
+	// strategy. Ids are then derived from random endpoints with the default
+	// hasher, and AddrLength has to be GeminusAddrLength. The nodes then
+	// also serve over one memory network, and a broadcast is measured for
+	// every redundancy in Redundancies.
+	//
+	// Every random draw, ids, endpoints, picks and the random forwards of
+	// routing, comes from Source, so a simulation run again from a source
+	// with the same seed replays exactly. A time seeded one if unset. The
+	// deliveries and broadcasts of Geminus nodes are the exception: their
+	// hops run concurrently and draw in whatever order they happen.
+	Config struct {
+		Nodes        int
+		AddrLength   int
+		Layout       Layout
+		Routes       int
+		MaxHops      int
+		Geminus      bool
+		Redundancies []int
+		Source       rand.Source
+	}
+
+	// Node is a simulated node: an id, its cases and the clubs it knows in
+	// a fully seeded network, which are those of its Geminus if it has one.
+	Node struct {
+		ID      Addressing.Addr
+		Cases   map[Gemini.Club]Addressing.Case
+		State   *Gemini.ClubState
+		Geminus *Gemini.Geminus
+	}
+
+	// Network is an omniscient view of every node, grouped by case so the
+	// clubs of a node are the group its cases select.
+	Network struct {
+		Config Config
+		Params *Gemini.GeminiConfig
+		Nodes  []*Node
+		Maps   map[Gemini.Club]map[Addressing.Case][]*Node
+
+		random *rand.Rand
+		byID   map[string]*Node
+	}
+)
+
+var ErrAddrLength = errors.New("The address length has to be a positive multiple of 8")
+
+// NewNetwork builds an empty network for config, filling the defaults in.
+func NewNetwork(config Config) (*Network, error) {
+	if config.AddrLength == 0 && config.Geminus {
+		config.AddrLength = GeminusAddrLength
+	}
+	if config.AddrLength == 0 {
+		config.AddrLength = DefaultAddrLength
+	}
+	if config.AddrLength < 0 || config.AddrLength%8 != 0 {
+		return nil, ErrAddrLength
+	}
+	if config.Routes == 0 {
+		config.Routes = DefaultRoutes
+	}
+	if config.MaxHops == 0 {
+		config.MaxHops = DefaultMaxHops
+	}
+	if config.Redundancies == nil {
+		config.Redundancies = DefaultRedundancies
+	}
+	if config.Source == nil {
+		config.Source = Tools.NewSource(time.Now().UnixNano())
+	}
+
+	params := Gemini.NewGeminiConfigWithCases(config.Nodes, config.AddrLength, config.Layout.Cases)
+	params.Strategy = config.Layout.Strategy
+	params.Source = config.Source
+	if err := params.ValidateCases(); err != nil {
+		return nil, err
+	}
+	if config.Geminus && config.AddrLength != GeminusAddrLength {
+		return nil, fmt.Errorf("Geminus nodes have %d bit ids", GeminusAddrLength)
+	}
+
+	return &Network{
+		Config: config,
+		Params: params,
+		Nodes:  make([]*Node, 0, config.Nodes),
+		Maps:   make(map[Gemini.Club]map[Addressing.Case][]*Node, len(config.Layout.Cases)),
+		random: rand.New(config.Source),
+		byID:   make(map[string]*Node, config.Nodes),
+	}, nil
+}
+
+// NewID draws a random id of addrLength bits.
+func NewID(random *rand.Rand, addrLength int) Addressing.Addr {
+	id := make([]byte, addrLength/8)
+	random.Read(id)
+	return &Addressing.Address{Raw: fmt.Sprintf("%x", id), Hashed: id, Status: Addressing.Hashed}
+}
+
+// Populate adds nodes with unique random ids until the network is full.
+func (n *Network) Populate() error {
+	for len(n.Nodes) < n.Config.Nodes {
+		node := &Node{Cases: make(map[Gemini.Club]Addressing.Case, len(n.Params.Cases))}
+
+		if n.Config.Geminus {
+			endpoint := fmt.Sprintf("%d.%d.%d.%d", n.random.Intn(256), n.random.Intn(256), n.random.Intn(256), n.random.Intn(256))
+			node.Geminus = Gemini.NewGeminus(endpoint, n.Params)
+			if err := node.Geminus.Init(); err != nil {
+				return err
+			}
+			node.ID = node.Geminus.Addr
+		} else {
+			node.ID = NewID(n.random, n.Config.AddrLength)
+		}
+
+		id := node.ID
+		if _, ok := n.byID[string(id.GetHash())]; ok {
+			continue
+		}
+
+		for _, cd := range n.Params.Cases {
+			node.Cases[cd.Name] = cd.Of(id)
+		}
+
+		n.Nodes = append(n.Nodes, node)
+		n.byID[string(id.GetHash())] = node
+	}
+
+	return nil
+}
+
+// Seed groups the nodes by case and hands every node the clubs it keeps:
+// for each case the other nodes whose peer case is its own case. A Geminus
+// gets them through AddAllInClub, so club bounds and eviction apply, and the
+// node state is what it ends up with.
+func (n *Network) Seed() {
+	for _, cd := range n.Params.Cases {
+		groups := make(map[Addressing.Case][]*Node)
+		for _, node := range n.Nodes {
+			c := cd.PeerCase(node.ID)
+			groups[c] = append(groups[c], node)
+		}
+		n.Maps[cd.Name] = groups
+	}
+
+	for _, node := range n.Nodes {
+		clubs := make(map[Gemini.Club][]Addressing.Addr, len(n.Params.Cases))
+		for _, cd := range n.Params.Cases {
+			members := n.Maps[cd.Name][node.Cases[cd.Name]]
+			club := make([]Addressing.Addr, 0, len(members))
+			for _, v := range members {
+				if v != node {
+					club = append(club, v.ID)
+				}
+			}
+			clubs[cd.Name] = club
+		}
+
+		if node.Geminus != nil {
+			for club, members := range clubs {
+				// the peers a full club refuses are what the survey measures
+				node.Geminus.AddAllInClub(club, members)
+				clubs[club], _ = node.Geminus.GetClub(club)
+			}
+		}
+
+		node.State = &Gemini.ClubState{Params: n.Params, Self: node.ID, Clubs: clubs}
+	}
+}
+
+// Lookup finds the node with the given id.
+func (n *Network) Lookup(id Addressing.Addr) (*Node, bool) {
+	node, ok := n.byID[string(id.GetHash())]
+	return node, ok
+}
+
+// PickRandom picks a random node.
+func (n *Network) PickRandom() *Node {
+	return n.Nodes[n.random.Intn(len(n.Nodes))]
+}
+
+// PickRandoms picks count random nodes, repeats allowed.
+func (n *Network) PickRandoms(count int) []*Node {
+	nodes := make([]*Node, 0, count)
+	for len(nodes) < count {
+		nodes = append(nodes, n.PickRandom())
+	}
+	return nodes
+}
@@ -0,0 +1,126 @@
+package sim
+
+import (
+	Gemini "gemelos/pkg/gemini"
+	"testing"
+)
+
+func newSeededNetwork(t *testing.T, name string, lengths ...int) *Network {
+	layout, err := NewLayout(name, DefaultAddrLength, lengths...)
+	if err != nil {
+		t.Fatal("Faulty layout", err)
+	}
+
+	network, err := NewNetwork(Config{Nodes: 500, Layout: layout, Routes: 200})
+	if err != nil {
+		t.Fatal("Faulty network", err)
+	}
+	if err := network.Populate(); err != nil {
+		t.Fatal("Faulty population", err)
+	}
+	network.Seed()
+	return network
+}
+
+func TestNewNetwork(t *testing.T) {
+	layout, _ := NewLayout("hatboot", DefaultAddrLength, 3, 3)
+
+	if _, err := NewNetwork(Config{Nodes: 10, AddrLength: 100, Layout: layout}); err != ErrAddrLength {
+		t.Log("Ids should be whole bytes", err)
+		t.Fail()
+	}
+
+	layout, _ = NewLayout("hatboot", DefaultAddrLength, 3, 100)
+	if _, err := NewNetwork(Config{Nodes: 10, Layout: layout}); err == nil {
+		t.Log("Cases wider than a Case should be refused")
+		t.Fail()
+	}
+}
+
+func TestSeed(t *testing.T) {
+	network := newSeededNetwork(t, "headtailreversed", 3, 3)
+
+	if len(network.Nodes) != 500 {
+		t.Log("The network should be populated to its size", len(network.Nodes))
+		t.Fail()
+	}
+
+	for _, node := range network.Nodes[:50] {
+		if _, ok := network.Lookup(node.ID); !ok {
+			t.Log("Every node should be found by id")
+			t.Fail()
+		}
+
+		for _, cd := range network.Params.Cases {
+			for _, v := range node.State.Clubs[cd.Name] {
+				if v == node.ID || cd.PeerCase(v) != cd.Of(node.ID) {
+					t.Log("A club should hold the other nodes whose peer case is ours", cd.Name)
+					t.Fail()
+				}
+			}
+		}
+	}
+
+	if len(network.Nodes[0].State.Clubs[Gemini.Tail]) == 0 {
+		t.Log("A node of a dense network should not be alone in its tail")
+		t.Fail()
+	}
+}
+
+func TestRoute(t *testing.T) {
+	layouts := map[string][]int{
+		"hatboot":          {3, 3},
+		"default":          {3, 3},
+		"headbodytail":     {3, 3, 3},
+		"headtailreversed": {3, 3},
+	}
+
+	for name, lengths := range layouts {
+		network := newSeededNetwork(t, name, lengths...)
+
+		source := network.Nodes[0]
+		for _, destination := range network.Nodes[1:100] {
+			route := network.Route(source, destination)
+			if !route.Routed || route.Hops > 3 || len(route.Statuses) != route.Hops {
+				t.Log("A dense network should route within a few hops", name, route.Hops, route.Statuses)
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestGeminus(t *testing.T) {
+	layout, _ := NewLayout("default", GeminusAddrLength, 3, 3)
+
+	if _, err := NewNetwork(Config{Nodes: 10, AddrLength: DefaultAddrLength, Layout: layout, Geminus: true}); err == nil {
+		t.Log("Geminus nodes should refuse ids their hasher does not make")
+		t.Fail()
+	}
+
+	network, err := NewNetwork(Config{Nodes: 500, Layout: layout, Geminus: true})
+	if err != nil {
+		t.Fatal("Faulty network", err)
+	}
+	if err := network.Populate(); err != nil {
+		t.Fatal("Faulty population", err)
+	}
+	network.Seed()
+
+	for _, node := range network.Nodes[:50] {
+		for _, cd := range network.Params.Cases {
+			if club, _ := node.Geminus.GetClub(cd.Name); len(club) == 0 || len(club) != len(node.State.Clubs[cd.Name]) {
+				t.Log("Every Geminus should hold its own clubs", cd.Name, len(club))
+				t.Fail()
+			}
+		}
+	}
+
+	source := network.Nodes[0]
+	for _, destination := range network.Nodes[1:100] {
+		route := network.Route(source, destination)
+		if !route.Routed || route.Hops > 3 {
+			t.Log("Geminus nodes should route a dense network within a few hops", route.Hops, route.Statuses)
+			t.Fail()
+		}
+	}
+}
@@ -4,13 +4,38 @@ import (
 	"encoding/binary"
 	"math/big"
 	rand "math/rand"
-	"time"
+	"sync"
 )
 
-func PickRandom(min, max int) int {
-	rand.Seed(time.Now().UnixNano())
-	randomInt := rand.Intn(max - min)
-	return randomInt
+// lockedSource makes a rand.Source safe for concurrent use.
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source64
+}
+
+// NewSource returns a rand.Source seeded with seed that can be shared
+// between goroutines. Two sources with the same seed draw the same numbers,
+// which is what makes a simulation or a routing scenario replayable.
+func NewSource(seed int64) rand.Source {
+	return &lockedSource{src: rand.NewSource(seed).(rand.Source64)}
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Uint64()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
 }
 
 func BigIntToUint64(b big.Int) uint64 {
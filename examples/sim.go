@@ -0,0 +1,117 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	Sim "gemelos/pkg/sim"
+	Tools "gemelos/pkg/tools"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// runSim is the sim command:
+//
+//	gemelos sim -layout hatboot -nodes 6000 -lengths 5,3
+//	gemelos sim -layout headbodytail -nodes 6000 -lengths 3,3,3
+//	gemelos sim -layout headtailreversed -nodes 6000 -lengths 4,4 -runs 10
+//	gemelos sim -layout default -nodes 6000 -lengths 5,3 -geminus
+//	gemelos sim -layout default -nodes 6000 -lengths 5,3 -geminus -redundancies 1,2,4
+//
+// Runs are seeded with the time unless -seed is given; the seed is printed
+// so any run can be replayed.
+func runSim(args []string) error {
+	flags := flag.NewFlagSet("sim", flag.ContinueOnError)
+	layoutName := flags.String("layout", "hatboot", "case layout and strategy, one of "+strings.Join(Sim.LayoutNames(), ", "))
+	nodes := flags.Int("nodes", 1000, "network size")
+	lengths := flags.String("lengths", "5,3", "case lengths in bits, comma separated, in layout order")
+	addrLength := flags.Int("addr-length", 0, fmt.Sprintf("id length in bits, %d by default or %d with -geminus", Sim.DefaultAddrLength, Sim.GeminusAddrLength))
+	routes := flags.Int("routes", Sim.DefaultRoutes, "routes to simulate per run")
+	maxHops := flags.Int("max-hops", Sim.DefaultMaxHops, "hops after which a route is given up")
+	runs := flags.Int("runs", 1, "runs to average the lonely islands over")
+	geminus := flags.Bool("geminus", false, "route and broadcast through one Gemini.Geminus per node")
+	redundancies := flags.String("redundancies", "2,4,8", "broadcast redundancies to measure with -geminus, comma separated")
+	seed := flags.Int64("seed", 0, "seed of every random draw, the time if 0")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *addrLength == 0 {
+		*addrLength = Sim.DefaultAddrLength
+		if *geminus {
+			*addrLength = Sim.GeminusAddrLength
+		}
+	}
+
+	caseLengths, err := parseInts(*lengths, "case length")
+	if err != nil {
+		return err
+	}
+	broadcastRedundancies, err := parseInts(*redundancies, "redundancy")
+	if err != nil {
+		return err
+	}
+
+	layout, err := Sim.NewLayout(*layoutName, *addrLength, caseLengths...)
+	if err != nil {
+		return err
+	}
+
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	fmt.Println("Seed:", *seed)
+
+	config := Sim.Config{
+		Nodes:        *nodes,
+		AddrLength:   *addrLength,
+		Layout:       layout,
+		Routes:       *routes,
+		MaxHops:      *maxHops,
+		Geminus:      *geminus,
+		Redundancies: broadcastRedundancies,
+		Source:       Tools.NewSource(*seed),
+	}
+
+	reports := make([]*Sim.Report, 0, *runs)
+	for i := 0; i < *runs; i++ {
+		report, err := Sim.Simulate(config)
+		if err != nil {
+			return err
+		}
+		reports = append(reports, report)
+	}
+
+	reports[len(reports)-1].Print(os.Stdout)
+
+	if *runs > 1 {
+		average := Sim.AverageLonelyIslands(reports)
+		kinds := make([]string, 0, len(average))
+		for kind := range average {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+
+		fmt.Printf("\n*) Average lonely islands stats on %d runs:\n", *runs)
+		for _, kind := range kinds {
+			fmt.Printf("*---> Alone in %s: %.1f\n", kind, average[kind])
+		}
+	}
+
+	return nil
+}
+
+// parseInts reads a comma separated list of what, such as case lengths.
+func parseInts(list, what string) ([]int, error) {
+	values := make([]int, 0)
+	for _, field := range strings.Split(list, ",") {
+		value, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, fmt.Errorf("Faulty %s %q", what, field)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}